@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -28,8 +29,26 @@ If --base is provided, only publishes skills that have changed since that git re
 		registryHost, _ := cmd.Flags().GetString("registry")
 		namespace, _ := cmd.Flags().GetString("namespace")
 
+		// Fall back to the registry settings in the config
 		if registryHost == "" || namespace == "" {
-			return fmt.Errorf("--registry and --namespace are required for batch publishing")
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current working directory: %w", err)
+			}
+			cfg, _, err := loadConfig(cwd, false)
+			if err != nil {
+				return err
+			}
+			if registryHost == "" {
+				registryHost = cfg.Registry
+			}
+			if namespace == "" {
+				namespace = cfg.RegistryFor(registryHost).Namespace
+			}
+		}
+
+		if registryHost == "" || namespace == "" {
+			return fmt.Errorf("--registry and --namespace are required for batch publishing (or set registry and registries.<host>.namespace in the config)")
 		}
 
 		// 1. Find all SKILL.md files
@@ -120,10 +139,11 @@ If --base is provided, only publishes skills that have changed since that git re
 			fmt.Printf("\nProcessing %s -> %v\n", skillName, tags)
 
 			absPath, _ := filepath.Abs(skillPath)
+			annotations := sourceAnnotations(absPath)
 
 			for _, tag := range tags {
 				// Build (idempotent content-wise, just updates tag reference)
-				if err := st.Build(ctx, absPath, tag, nil); err != nil {
+				if err := st.Build(ctx, absPath, tag, annotations); err != nil {
 					fmt.Printf("Build failure for %s: %v\n", tag, err)
 					errs = append(errs, fmt.Errorf("build failed for %s: %w", tag, err))
 					continue
@@ -149,10 +169,9 @@ If --base is provided, only publishes skills that have changed since that git re
 
 func init() {
 	batchCmd.AddCommand(batchPublishCmd)
+	addRegistryFlags(batchPublishCmd)
 	batchPublishCmd.Flags().String("base", "", "Git reference to compare against (e.g. origin/main)")
-	batchPublishCmd.Flags().String("registry", "", "Registry host (e.g. ghcr.io)")
-	batchPublishCmd.Flags().String("namespace", "", "Registry namespace (e.g. user or org)")
+	batchPublishCmd.Flags().String("registry", "", "Registry host (e.g. ghcr.io, default: registry from the config)")
+	batchPublishCmd.Flags().String("namespace", "", "Registry namespace (e.g. user or org, default: the registry's namespace from the config)")
 	batchPublishCmd.Flags().String("repository", "", "Repository name (optional, enables repo.skill naming)")
-	batchPublishCmd.MarkFlagRequired("registry")
-	batchPublishCmd.MarkFlagRequired("namespace")
 }
@@ -1,13 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os/exec"
-	"strings"
 
+	"github.com/andrewhowdencom/skr/pkg/git"
 	"github.com/andrewhowdencom/skr/pkg/skill"
 	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -50,33 +49,12 @@ If [path] is not provided, defaults to the current directory.`,
 			return fmt.Errorf("failed to initialize store: %w", err)
 		}
 
-		// Detect Git Remote for source annotation
-		annotations := make(map[string]string)
-		if sourceURL, err := getGitRemoteURL(); err == nil && sourceURL != "" {
-			annotations["org.opencontainers.image.source"] = sourceURL
+		// Detect the git source. The skill's own metadata is added by the store.
+		annotations := sourceAnnotations(s.Path)
+		if sourceURL := annotations[ocispec.AnnotationSource]; sourceURL != "" {
 			fmt.Printf("Detected git source: %s\n", sourceURL)
 		}
 
-		// Add Metadata Annotations
-		if s.Metadata.Author != "" {
-			annotations["com.skr.author"] = s.Metadata.Author
-		}
-		if s.Metadata.Version != "" {
-			annotations["com.skr.version"] = s.Metadata.Version
-		}
-		if s.Description != "" {
-			annotations["com.skr.description"] = s.Description
-		}
-
-		// Add Dependencies Annotation
-		if len(s.Dependencies) > 0 {
-			depsJSON, err := json.Marshal(s.Dependencies)
-			if err != nil {
-				return fmt.Errorf("failed to marshal dependencies: %w", err)
-			}
-			annotations["com.skr.dependencies"] = string(depsJSON)
-		}
-
 		if err := st.Build(ctx, s.Path, buildTag, annotations); err != nil {
 			return fmt.Errorf("failed to build artifact: %w", err)
 		}
@@ -93,22 +71,15 @@ func init() {
 	buildCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "Tag for the built artifact (e.g., registry.com/skill:v1)")
 }
 
-func getGitRemoteURL() (string, error) {
-	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
-	out, err := cmd.Output()
-	if err != nil {
-		return "", err
+// sourceAnnotations returns the annotations identifying the git source of the skill in dir,
+// if it is in a git repository.
+func sourceAnnotations(dir string) map[string]string {
+	annotations := make(map[string]string)
+	if sourceURL, err := git.GetRemoteURL(dir); err == nil && sourceURL != "" {
+		annotations[ocispec.AnnotationSource] = sourceURL
 	}
-	url := strings.TrimSpace(string(out))
-	// Convert SSH URL to HTTPS if needed? GitHub packages supports both but HTTPS is standard for source linking?
-	// Actually GHCR works with repo URL.
-	// git@github.com:user/repo.git -> https://github.com/user/repo
-	if strings.HasPrefix(url, "git@github.com:") {
-		url = strings.Replace(url, "git@github.com:", "https://github.com/", 1)
-		url = strings.TrimSuffix(url, ".git")
-	} else if strings.HasPrefix(url, "https://github.com/") {
-		url = strings.TrimSuffix(url, ".git")
+	if revision, err := git.GetRevision(dir); err == nil && revision != "" {
+		annotations[ocispec.AnnotationRevision] = revision
 	}
-
-	return url, nil
+	return annotations
 }
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/andrewhowdencom/skr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage skr configuration",
+	Long: `Inspect, edit and validate the skr configuration files (.skr.yaml and the global config.yaml).
+
+Edits are made in place, keeping the comments and the order of keys in the file.`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
+
+// loadConfig loads the effective configuration for startDir, including the file given by --config
+// and environment overrides.
+func loadConfig(startDir string, global bool) (*config.Config, config.Origins, error) {
+	return config.LoadLayered(config.LoadOptions{
+		StartDir:     startDir,
+		ExplicitPath: configPath,
+		Global:       global,
+	})
+}
+
+// configDocument opens the configuration file that config edits apply to: the user's config
+// if global is set, the file given by --config, or the nearest .skr.yaml (created in the
+// current directory if there is none).
+func configDocument(global bool) (*config.Document, error) {
+	path := configPath
+	if global {
+		path = config.UserConfigPath()
+		if path == "" {
+			return nil, fmt.Errorf("failed to determine the user configuration directory")
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create config directory: %w", err)
+		}
+	}
+
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		path, err = config.FindConfigFile(cwd)
+		if err != nil {
+			path = filepath.Join(cwd, config.AltConfigName)
+		}
+	}
+
+	return config.LoadDocument(path)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var configAddCmd = &cobra.Command{
+	Use:   "add <key> <value>...",
+	Short: "Add values to a configuration list",
+	Long: `Add values to a list in the configuration file, e.g.
+
+  skr config add agents roocode
+  skr config add skills ghcr.io/my-org/skills.go:v1
+
+Values that are already in the list are skipped.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		isGlobal, _ := cmd.Flags().GetBool("global")
+
+		doc, err := configDocument(isGlobal)
+		if err != nil {
+			return err
+		}
+
+		if err := doc.Add(args[0], args[1:]...); err != nil {
+			return err
+		}
+		if err := doc.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Updated %s in %s\n", args[0], doc.Path())
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configAddCmd)
+	configAddCmd.Flags().Bool("global", false, "Use the global configuration")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: `Print the value of a key in the configuration file.
+
+Keys are dotted paths, e.g. install_mode, agents, registries.ghcr.io.namespace or
+skills.<ref>.pin. Lists and mappings are printed as YAML.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		isGlobal, _ := cmd.Flags().GetBool("global")
+
+		doc, err := configDocument(isGlobal)
+		if err != nil {
+			return err
+		}
+
+		node, err := doc.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		if node.Kind == yaml.ScalarNode {
+			fmt.Println(node.Value)
+			return nil
+		}
+
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return fmt.Errorf("failed to print %s: %w", args[0], err)
+		}
+		return enc.Close()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configGetCmd.Flags().Bool("global", false, "Use the global configuration")
+}
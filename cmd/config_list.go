@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the values in a configuration file",
+	Long: `List every value in the configuration file as key=value, one per line.
+
+Items of a list are printed once each under the key of the list. To see the effective
+configuration across all files, use skr config show.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		isGlobal, _ := cmd.Flags().GetBool("global")
+
+		doc, err := configDocument(isGlobal)
+		if err != nil {
+			return err
+		}
+
+		for _, kv := range doc.List() {
+			fmt.Printf("%s=%s\n", kv.Key, kv.Value)
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configListCmd)
+	configListCmd.Flags().Bool("global", false, "Use the global configuration")
+}
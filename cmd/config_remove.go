@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var configRemoveCmd = &cobra.Command{
+	Use:   "remove <key> [value]...",
+	Short: "Remove configuration values",
+	Long: `Remove values from a list in the configuration file, or remove a key entirely, e.g.
+
+  skr config remove agents roocode
+  skr config remove skills ghcr.io/my-org/skills.go:v1
+  skr config remove registries.ghcr.io
+
+Skills can be removed by reference or by name.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		isGlobal, _ := cmd.Flags().GetBool("global")
+
+		doc, err := configDocument(isGlobal)
+		if err != nil {
+			return err
+		}
+
+		if err := doc.Remove(args[0], args[1:]...); err != nil {
+			return err
+		}
+		if err := doc.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Updated %s in %s\n", args[0], doc.Path())
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configRemoveCmd)
+	configRemoveCmd.Flags().Bool("global", false, "Use the global configuration")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Set a single value in the configuration file, e.g.
+
+  skr config set install_mode symlink
+  skr config set registries.ghcr.io.namespace my-org
+  skr config set skills.ghcr.io/my-org/skills.go:v1.optional true
+
+Use add and remove to change lists.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		isGlobal, _ := cmd.Flags().GetBool("global")
+
+		doc, err := configDocument(isGlobal)
+		if err != nil {
+			return err
+		}
+
+		if err := doc.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := doc.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Set %s in %s\n", args[0], doc.Path())
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+	configSetCmd.Flags().Bool("global", false, "Use the global configuration")
+}
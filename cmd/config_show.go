@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long: `Show the configuration that results from merging every configuration layer:
+
+1. System (skr/config.yaml in $XDG_CONFIG_DIRS)
+2. User (~/.config/skr/config.yaml)
+3. Every .skr.yaml from the filesystem root down to the current directory
+4. The file given by --config
+5. Environment variables (SKR_AGENTS, SKR_SKILLS, SKR_INSTALL_MODE)
+
+With --origin, each value is annotated with the file that contributed it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		showOrigin, _ := cmd.Flags().GetBool("origin")
+		isGlobal, _ := cmd.Flags().GetBool("global")
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+
+		cfg, origins, err := loadConfig(cwd, isGlobal)
+		if err != nil {
+			return err
+		}
+
+		var out any = cfg
+		if showOrigin {
+			out, err = origins.Annotate(cfg)
+			if err != nil {
+				return err
+			}
+		}
+
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(out); err != nil {
+			return fmt.Errorf("failed to print config: %w", err)
+		}
+		return enc.Close()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	configShowCmd.Flags().Bool("origin", false, "Annotate each value with the file that set it")
+	configShowCmd.Flags().Bool("global", false, "Ignore project configuration files")
+}
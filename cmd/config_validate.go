@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/andrewhowdencom/skr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate a configuration file",
+	Long: `Validate a configuration file against the skr configuration schema.
+
+Reports unknown keys, values of the wrong type and invalid skill references,
+each with its line and column.
+
+If [path] is not provided, validates the nearest .skr.yaml.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var path string
+		if len(args) > 0 {
+			path = args[0]
+		} else {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current working directory: %w", err)
+			}
+			path, err = config.FindConfigFile(cwd)
+			if err != nil {
+				return fmt.Errorf("no configuration file found (searching up from %s)", cwd)
+			}
+		}
+
+		problems, err := config.ValidateFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range problems {
+			fmt.Printf("%s:%s\n", path, p)
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("configuration %s has %d problem(s)", path, len(problems))
+		}
+
+		fmt.Printf("Configuration %s is valid.\n", path)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
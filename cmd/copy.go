@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/andrewhowdencom/skr/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+var copyRecursive bool
+
+var copyCmd = &cobra.Command{
+	Use:   "copy <src-ref> <dst-ref>",
+	Short: "Copy an Agent Skill between registries",
+	Long: `Copy an Agent Skill artifact from one registry (or namespace) to another, without
+going through the local store. Referrers of the skill, such as signatures and SBOMs,
+are copied along with it. If <dst-ref> has no tag, the tag of <src-ref> is used.
+
+With --recursive, the dependencies of the skill (and theirs) are copied to the
+namespace of <dst-ref> as well, and the dependencies of the copies are rewritten to
+refer to them. Dependencies in the namespace of <src-ref> keep their path below it;
+others keep the last element of their repository:
+
+  skr copy --recursive ghcr.io/acme/staging/pdf:1.0.0 ghcr.io/acme/prod/pdf
+
+A skill whose dependencies are rewritten gets a new digest, so its referrers no
+longer apply to it and are not copied.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Printf("Copying %s to %s...\n", args[0], args[1])
+		results, err := registry.Copy(cmd.Context(), args[0], args[1], registry.CopyOptions{Recursive: copyRecursive})
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			fmt.Printf("Copied %s to %s (%s)\n", result.Source, result.Destination, result.Descriptor.Digest)
+			if result.Rewritten {
+				fmt.Printf("  Dependencies rewritten; referrers of %s were not copied\n", result.Source)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+	copyCmd.Flags().BoolVarP(&copyRecursive, "recursive", "r", false, "Also copy the dependencies, and rewrite them to refer to the copies")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/andrewhowdencom/skr/pkg/diff"
+	"github.com/andrewhowdencom/skr/pkg/registry"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+var diffStat bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <refA> <refB>",
+	Short: "Show the changes between two Agent Skills",
+	Long: `Show the changes between two Agent Skill artifacts, such as two tags of a skill.
+
+References are looked up in the local store first, and fetched from their registry
+otherwise; nothing is written to the store. The annotation and dependency changes
+are shown first, then a unified diff of every changed text file, starting with
+SKILL.md. Binary files are compared by digest.
+
+With --stat, only a summary of the changed files is shown.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		st, err := store.New("")
+		if err != nil {
+			return fmt.Errorf("failed to initialize store: %w", err)
+		}
+
+		from, err := loadDiffArtifact(ctx, st, args[0])
+		if err != nil {
+			return err
+		}
+		to, err := loadDiffArtifact(ctx, st, args[1])
+		if err != nil {
+			return err
+		}
+
+		d, err := diff.Compare(from, to)
+		if err != nil {
+			return err
+		}
+		if d.Empty() {
+			fmt.Println("No changes.")
+			return nil
+		}
+		if diffStat {
+			d.WriteStat(os.Stdout)
+		} else {
+			d.Write(os.Stdout)
+		}
+		return nil
+	},
+}
+
+// loadDiffArtifact reads ref from the local store, or from its registry if it is not in the
+// store.
+func loadDiffArtifact(ctx context.Context, st *store.Store, ref string) (*diff.Artifact, error) {
+	if desc, err := st.Resolve(ctx, ref); err == nil {
+		return diff.Load(ctx, st, ref, desc)
+	}
+
+	repo, desc, err := registry.Resolve(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("reference %s not found in local store or registry: %w", ref, err)
+	}
+	return diff.Load(ctx, repo, ref, desc)
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Show a summary of the changed files only")
+}
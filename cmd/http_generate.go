@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/andrewhowdencom/skr/pkg/index"
 	"github.com/andrewhowdencom/skr/pkg/store"
 	"github.com/andrewhowdencom/skr/pkg/ui"
 	"github.com/spf13/cobra"
@@ -20,7 +21,12 @@ var outputDir string
 var httpGenerateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate the static UI site",
-	Long:  `Generate a static HTML website with a static OCI registry file structure, enabling the UI to run without a backend.`,
+	Long: `Generate a static HTML website with a static OCI registry file structure, enabling the UI to run without a backend.
+
+The site includes a search index (index.json) of every skill, which the UI and
+'skr search' use instead of listing the registry, and a page for every skill
+(skills/<repository>/) with its rendered SKILL.md, files, dependencies and versions,
+and the changes between every two versions.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
@@ -147,6 +153,60 @@ var httpGenerateCmd = &cobra.Command{
 			}
 		}
 
+		// 3.3 Search index, used by the UI and skr search
+		idx, err := index.FromStore(ctx, st, index.Options{Content: true})
+		if err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
+		indexPath := filepath.Join(outputDir, index.FileName)
+		if err := writeJSON(indexPath, idx); err != nil {
+			return err
+		}
+		fmt.Printf("Generated %s (%d skills)\n", indexPath, len(idx.Skills))
+
+		// 3.4 Skill pages
+		for _, s := range idx.Skills {
+			page, files, err := ui.LoadSkill(ctx, st, idx, s)
+			if err != nil {
+				fmt.Printf("Warning: could not render %s: %v\n", s.Repository, err)
+				continue
+			}
+
+			pagePath, err := sitePath(outputDir, ui.SkillPath(s.Repository)+"index.html")
+			if err != nil {
+				return err
+			}
+			if err := writePage(pagePath, func(w io.Writer) error { return ui.WriteSkillPage(w, page) }); err != nil {
+				return err
+			}
+			for _, f := range files {
+				filePath, err := sitePath(outputDir, ui.FilePath(s.Repository, f.Path))
+				if err != nil {
+					return err
+				}
+				if err := writePage(filePath, func(w io.Writer) error { return ui.WriteFilePage(w, f) }); err != nil {
+					return err
+				}
+			}
+
+			// Every version can be compared with every other one
+			diffs, err := ui.LoadDiffs(ctx, st, s)
+			if err != nil {
+				fmt.Printf("Warning: could not compare versions of %s: %v\n", s.Repository, err)
+				continue
+			}
+			for _, d := range diffs {
+				diffPath, err := sitePath(outputDir, ui.DiffPath(s.Repository, d.From, d.To))
+				if err != nil {
+					return err
+				}
+				if err := writePage(diffPath, func(w io.Writer) error { return ui.WriteDiffPage(w, d) }); err != nil {
+					return err
+				}
+			}
+		}
+		fmt.Printf("Generated %d skill pages\n", len(idx.Skills))
+
 		// 4. Copy UI Assets
 		assets, err := ui.Assets()
 		if err != nil {
@@ -180,6 +240,31 @@ func writeJSON(path string, data interface{}) error {
 	return json.NewEncoder(f).Encode(data)
 }
 
+// sitePath returns the path of the page at p (relative to the site root, with forward
+// slashes) in outputDir. Pages that would be written outside outputDir are an error.
+func sitePath(outputDir, p string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(p)) {
+		return "", fmt.Errorf("refusing to write %s outside of the output directory", p)
+	}
+	return filepath.Join(outputDir, filepath.FromSlash(p)), nil
+}
+
+// writePage creates the file at path, and its directory, with the output of render.
+func writePage(path string, render func(w io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := render(f); err != nil {
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return nil
+}
+
 func copyAsset(assets fs.FS, src, dest string) error {
 	f, err := assets.Open(src)
 	if err != nil {
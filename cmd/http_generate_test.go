@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSitePath(t *testing.T) {
+	got, err := sitePath("out", "skills/ghcr.io/acme/skill/files/SKILL.md.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("out", "skills", "ghcr.io", "acme", "skill", "files", "SKILL.md.html"); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	for _, p := range []string{"skills/acme/files/../../../../x.html", "/etc/x.html"} {
+		if _, err := sitePath("out", p); err == nil {
+			t.Errorf("expected an error for %s", p)
+		}
+	}
+}
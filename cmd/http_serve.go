@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/andrewhowdencom/skr/pkg/index"
 	"github.com/andrewhowdencom/skr/pkg/instrumentation"
 	"github.com/andrewhowdencom/skr/pkg/store"
 	"github.com/andrewhowdencom/skr/pkg/ui"
@@ -29,7 +32,11 @@ var traceEndpoint string
 var httpServeCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Serve the Skills Registry UI locally",
-	Long:  `Start a local HTTP server that builds and serves the UI on-the-fly, reflecting the current state of the OCI store.`,
+	Long: `Start a local HTTP server that builds and serves the UI on-the-fly, reflecting the current state of the OCI store.
+
+The search index (/index.json) is built from the store when it is requested, and
+rebuilt whenever the store changes. When proxying, it is fetched from the remote
+registry.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
@@ -94,6 +101,14 @@ var httpServeCmd = &cobra.Command{
 		// 4. OCI Registry Handlers
 		mux.HandleFunc("/v2/", newOCIHandler(ctx, st, proxy))
 
+		// 5. Search Index and Skill Pages
+		var si *storeIndex
+		if st != nil {
+			si = &storeIndex{st: st}
+		}
+		mux.HandleFunc("/"+index.FileName, newIndexHandler(si, proxy))
+		mux.HandleFunc("/skills/", newSkillPageHandler(si, proxy))
+
 		// 6. UI Assets
 		mux.Handle("/", fileServer)
 
@@ -303,3 +318,172 @@ func newOCIHandler(GlobalCtx context.Context, st *store.Store, proxy *httputil.R
 		http.NotFound(w, r)
 	}
 }
+
+// storeIndex builds the search index of a store. The index is cached until the tags in the
+// store change.
+type storeIndex struct {
+	st *store.Store
+
+	mu   sync.Mutex
+	key  string
+	idx  *index.Index
+	data []byte
+}
+
+// get returns the index, and its JSON encoding.
+func (si *storeIndex) get(ctx context.Context) (*index.Index, []byte, error) {
+	tags, err := si.st.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	// The index only changes when a tag is added, removed or moved
+	var key strings.Builder
+	for _, t := range tags {
+		desc, err := si.st.Resolve(ctx, t)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&key, "%s@%s\n", t, desc.Digest)
+	}
+
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	if si.idx == nil || key.String() != si.key {
+		idx, err := index.FromStore(ctx, si.st, index.Options{Content: true})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build index: %w", err)
+		}
+		data, err := json.Marshal(idx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode index: %w", err)
+		}
+		si.idx, si.data, si.key = idx, data, key.String()
+	}
+	return si.idx, si.data, nil
+}
+
+// newIndexHandler serves the search index of the store, or proxies it from the remote
+// registry.
+func newIndexHandler(si *storeIndex, proxy *httputil.ReverseProxy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		if proxy != nil {
+			proxy.ServeHTTP(w, r)
+			return
+		}
+
+		_, data, err := si.get(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
+
+// newSkillPageHandler serves the detail pages of skills (/skills/<repo>/), their files
+// (/skills/<repo>/files/<path>.html) and the changes between their versions
+// (/skills/<repo>/diff/<from>/<to>.html), or proxies them from the remote registry.
+func newSkillPageHandler(si *storeIndex, proxy *httputil.ReverseProxy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if proxy != nil {
+			proxy.ServeHTTP(w, r)
+			return
+		}
+
+		p := strings.TrimPrefix(r.URL.Path, "/skills/")
+		var repo, file, from, to string
+		if i := strings.Index(p, "/files/"); i != -1 && strings.HasSuffix(p, ".html") {
+			repo, file = p[:i], strings.TrimSuffix(p[i+len("/files/"):], ".html")
+		} else if i := strings.LastIndex(p, "/diff/"); i != -1 && strings.HasSuffix(p, ".html") {
+			var ok bool
+			repo = p[:i]
+			from, to, ok = strings.Cut(strings.TrimSuffix(p[i+len("/diff/"):], ".html"), "/")
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+		} else {
+			trimmed := strings.TrimSuffix(p, "index.html")
+			if !strings.HasSuffix(trimmed, "/") {
+				// Links on the page are relative to the skill's directory
+				http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+				return
+			}
+			repo = strings.TrimSuffix(trimmed, "/")
+		}
+
+		ctx := r.Context()
+		idx, _, err := si.get(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var s *index.Skill
+		for i := range idx.Skills {
+			if idx.Skills[i].Repository == repo {
+				s = &idx.Skills[i]
+				break
+			}
+		}
+		if s == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		if from != "" {
+			if !hasVersion(s, from) || !hasVersion(s, to) {
+				http.NotFound(w, r)
+				return
+			}
+			page, err := ui.LoadDiff(ctx, si.st, *s, from, to)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if err := ui.WriteDiffPage(w, page); err != nil {
+				slog.Error("failed to render page", "path", r.URL.Path, "error", err)
+			}
+			return
+		}
+
+		page, files, err := ui.LoadSkill(ctx, si.st, idx, *s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if file == "" {
+			if err := ui.WriteSkillPage(w, page); err != nil {
+				slog.Error("failed to render page", "path", r.URL.Path, "error", err)
+			}
+			return
+		}
+		for _, f := range files {
+			if f.Path == file {
+				if err := ui.WriteFilePage(w, f); err != nil {
+					slog.Error("failed to render page", "path", r.URL.Path, "error", err)
+				}
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}
+}
+
+// hasVersion reports whether s has a version tagged tag.
+func hasVersion(s *index.Skill, tag string) bool {
+	for _, v := range s.Versions {
+		if v.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -8,8 +8,10 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/andrewhowdencom/skr/pkg/index"
 	"github.com/andrewhowdencom/skr/pkg/store"
 	"oras.land/oras-go/v2/content/oci"
 )
@@ -121,3 +123,51 @@ func TestServeOCIEndpoints(t *testing.T) {
 		}
 	})
 }
+
+func TestServeIndex(t *testing.T) {
+	tmpDir, st := createTestStore(t)
+	defer os.RemoveAll(tmpDir)
+
+	ctx := context.Background()
+	handler := newIndexHandler(&storeIndex{st: st}, nil)
+
+	get := func() index.Index {
+		req := httptest.NewRequest("GET", "/index.json", nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 OK, got %d: %s", w.Code, w.Body.String())
+		}
+		var idx index.Index
+		if err := json.NewDecoder(w.Body).Decode(&idx); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return idx
+	}
+
+	if idx := get(); len(idx.Skills) != 0 {
+		t.Errorf("Expected no skills, got %v", idx.Skills)
+	}
+
+	// The index is rebuilt once the store changes
+	srcDir := filepath.Join(t.TempDir(), "pdf")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: pdf\ndescription: Work with PDF files\n---\n# PDF\n\nUse pdfplumber.\n"
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Build(ctx, srcDir, "localhost/skills/pdf:v1", nil); err != nil {
+		t.Fatalf("Failed to build skill: %v", err)
+	}
+
+	idx := get()
+	if len(idx.Skills) != 1 {
+		t.Fatalf("Expected 1 skill, got %v", idx.Skills)
+	}
+	if results := idx.Search("pdfplumb"); len(results) != 1 {
+		t.Errorf("Expected the body to be searchable, got %v", idx.Skills[0].Tokens)
+	}
+}
@@ -40,8 +40,9 @@ directory structure for agent skills (e.g., .agent/skills).`,
 
 		// Create .skr.yaml
 		cfg := &config.Config{
-			Agents: []string{initAgent},
-			Skills: []string{},
+			Version: config.CurrentVersion,
+			Agents:  []string{initAgent},
+			Skills:  []config.SkillEntry{},
 		}
 
 		// Save uses SaveTo internally with .skr.yaml
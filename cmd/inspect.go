@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andrewhowdencom/skr/pkg/registry"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+var inspectFiles bool
+
+var remoteInspectCmd = &cobra.Command{
+	Use:   "inspect [ref]",
+	Short: "Inspect an Agent Skill in a registry",
+	Long: `Inspect an Agent Skill in a remote registry, without pulling it.
+
+Only the manifest and config are downloaded. Shows the annotations, dependencies,
+size and creation time of the skill, the artifacts that refer to it (such as
+signatures), and all tags in its repository.
+
+With --files, the skill layer is streamed to list its contents. Nothing is written
+to the local store. Use 'skr system inspect' for skills in the local store.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref := args[0]
+		ctx := cmd.Context()
+
+		a, err := registry.Inspect(ctx, ref)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Reference: %s\n", ref)
+		fmt.Printf("Digest: %s\n", a.Descriptor.Digest)
+		fmt.Printf("MediaType: %s\n", a.Descriptor.MediaType)
+		if a.Manifest.ArtifactType != "" {
+			fmt.Printf("ArtifactType: %s\n", a.Manifest.ArtifactType)
+		}
+		fmt.Printf("Size: %d bytes\n", a.Size())
+
+		created := store.Annotation(a.Manifest.Annotations, ocispec.AnnotationCreated)
+		if a.Config != nil && !a.Config.Created.IsZero() {
+			created = a.Config.Created.Format(time.RFC3339)
+		}
+		if created != "" {
+			fmt.Printf("Created: %s\n", created)
+		}
+		for _, field := range []struct{ label, key string }{
+			{"Description", ocispec.AnnotationDescription},
+			{"Authors", ocispec.AnnotationAuthors},
+			{"Version", ocispec.AnnotationVersion},
+			{"Source", ocispec.AnnotationSource},
+			{"Revision", ocispec.AnnotationRevision},
+		} {
+			if v := store.Annotation(a.Manifest.Annotations, field.key); v != "" {
+				fmt.Printf("%s: %s\n", field.label, v)
+			}
+		}
+
+		if depsJSON, ok := a.Manifest.Annotations[store.AnnotationDependencies]; ok {
+			var deps []string
+			if err := json.Unmarshal([]byte(depsJSON), &deps); err == nil && len(deps) > 0 {
+				fmt.Println("\nDependencies:")
+				for _, dep := range deps {
+					fmt.Printf("  %s\n", dep)
+				}
+			}
+		}
+
+		if len(a.Manifest.Annotations) > 0 {
+			fmt.Println("\nAnnotations:")
+			keys := make([]string, 0, len(a.Manifest.Annotations))
+			for k := range a.Manifest.Annotations {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Printf("  %s: %s\n", k, a.Manifest.Annotations[k])
+			}
+		}
+
+		if a.ReferrersErr != nil {
+			fmt.Printf("\nReferrers: unavailable (%v)\n", a.ReferrersErr)
+		} else {
+			fmt.Printf("\nReferrers: %d\n", len(a.Referrers))
+			for _, referrer := range a.Referrers {
+				fmt.Printf("  %s %s\n", referrer.Digest, referrer.ArtifactType)
+			}
+		}
+
+		if a.TagsErr != nil {
+			fmt.Printf("\nTags: unavailable (%v)\n", a.TagsErr)
+		} else {
+			fmt.Printf("\nTags: %d\n", len(a.Tags))
+			for _, tag := range a.Tags {
+				fmt.Printf("  %s\n", tag)
+			}
+		}
+
+		if inspectFiles {
+			files, err := a.Files(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nFiles: %d\n", len(files))
+			for _, f := range files {
+				switch f.Type {
+				case "symlink":
+					fmt.Printf("  %s %8s %s -> %s\n", f.Mode, "", f.Path, f.Target)
+				case "file":
+					fmt.Printf("  %s %8d %s\n", f.Mode, f.Size, f.Path)
+				default:
+					fmt.Printf("  %s %8s %s/\n", f.Mode, "", f.Path)
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(remoteInspectCmd)
+	remoteInspectCmd.Flags().BoolVar(&inspectFiles, "files", false, "Stream the skill layer and list its contents")
+}
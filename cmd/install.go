@@ -18,7 +18,8 @@ var installCmd = &cobra.Command{
 	Short: "Install an Agent Skill",
 	Long: `Install an Agent Skill.
 
-Adds the skill to the configuration (.skr.yaml) and synchronizes the installation.
+Adds the skill to the configuration (.skr.yaml) and synchronizes the installation,
+including into the skill directory of every agent listed in the configuration.
 If --global is set, installs to the global configuration.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
@@ -31,6 +32,7 @@ If --global is set, installs to the global configuration.`,
 		// 1. Determine Context and Load Config
 		var configFilePath string
 		var installRoot string
+		var projectRoot string
 
 		if isGlobal {
 			homeDir, err := os.UserHomeDir()
@@ -58,8 +60,8 @@ If --global is set, installs to the global configuration.`,
 				// Let's error for now.
 				return fmt.Errorf("agent context not found (use --global or run inside a project): %w", err)
 			}
-			configDir := filepath.Dir(filepath.Dir(agentDir))               // Parent of .agent
-			configFilePath = filepath.Join(configDir, config.AltConfigName) // .skr.yaml
+			projectRoot = filepath.Dir(filepath.Dir(agentDir))                // Parent of .agent
+			configFilePath = filepath.Join(projectRoot, config.AltConfigName) // .skr.yaml
 
 			installRoot = agentDir
 		}
@@ -82,14 +84,23 @@ If --global is set, installs to the global configuration.`,
 		// Check if already exists?
 		exists := false
 		for _, s := range cfg.Skills {
-			if s == ref {
+			if s.Matches(ref) {
 				exists = true
 				break
 			}
 		}
 		if !exists {
-			cfg.Skills = append(cfg.Skills, ref)
-			if err := cfg.SaveTo(configFilePath); err != nil {
+			cfg.Skills = append(cfg.Skills, config.SkillEntry{Ref: ref})
+
+			// Edit the file in place, so that its comments are kept
+			doc, err := config.LoadDocument(configFilePath)
+			if err != nil {
+				return err
+			}
+			if err := doc.Add("skills", ref); err != nil {
+				return fmt.Errorf("failed to add skill to config: %w", err)
+			}
+			if err := doc.Save(); err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
 			}
 			slog.Info("added skill to config", "skill", ref, "config", configFilePath)
@@ -108,12 +119,25 @@ If --global is set, installs to the global configuration.`,
 			return fmt.Errorf("failed to initialize store: %w", err)
 		}
 
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		targets, err := cfg.AgentTargets(projectRoot, home, isGlobal)
+		if err != nil {
+			return err
+		}
+
 		slog.Info("installing skill", "skill", ref, "path", installRoot)
-		name, err := action.InstallSkill(ctx, st, ref, installRoot)
+		name, err := action.InstallSkillForAgents(ctx, st, ref, installRoot, config.TargetDirs(targets), cfg.InstallMode)
 		if err != nil {
 			return err
 		}
 
+		if err := renderAgents(cfg, targets); err != nil {
+			return err
+		}
+
 		slog.Info("successfully installed skill", "name", name, "ref", ref)
 		return nil
 	},
@@ -122,4 +146,5 @@ If --global is set, installs to the global configuration.`,
 func init() {
 	installCmd.Flags().Bool("global", false, "Install skill globally")
 	rootCmd.AddCommand(installCmd)
+	addRegistryFlags(installCmd)
 }
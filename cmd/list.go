@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 	"text/tabwriter"
 
-	"github.com/andrewhowdencom/skr/pkg/config"
 	"github.com/andrewhowdencom/skr/pkg/discovery"
 	"github.com/spf13/cobra"
 )
@@ -24,7 +23,7 @@ Scans the hierarchy for .agent/skills and merges with global skills.`,
 		}
 
 		// Load config to get agent paths
-		cfg, err := config.LoadMerged(cwd)
+		cfg, _, err := loadConfig(cwd, false)
 		if err != nil {
 			// If error, maybe just proceed? Or partial load?
 			// But LoadMerged calls Load which returns default if not found.
@@ -36,10 +35,9 @@ Scans the hierarchy for .agent/skills and merges with global skills.`,
 		var extraPaths []string
 		home, err := os.UserHomeDir()
 		if err == nil {
-			for _, agentName := range cfg.Agents {
-				if pathFunc, ok := config.KnownAgents[agentName]; ok {
-					extraPaths = append(extraPaths, pathFunc(home))
-				}
+			extraPaths, err = cfg.AgentDirs(cwd, home, true)
+			if err != nil {
+				return err
 			}
 		}
 
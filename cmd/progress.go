@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andrewhowdencom/skr/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/term"
+)
+
+// progressRedrawInterval limits how often progress bars are redrawn.
+const progressRedrawInterval = 100 * time.Millisecond
+
+// progressBarWidth is the width of a progress bar, in characters.
+const progressBarWidth = 30
+
+// transferProgress renders the progress of a push or pull: a progress bar for every blob on
+// a terminal, and a line for every blob that is done otherwise.
+type transferProgress struct {
+	w     io.Writer
+	tty   bool
+	stats registry.Stats
+
+	// The blobs shown on the terminal, in the order they started, and their last line
+	blobs    []digest.Digest
+	lines    map[digest.Digest]string
+	drawn    int
+	lastDraw time.Time
+}
+
+// newTransferProgress returns a transferProgress writing to stdout.
+func newTransferProgress() *transferProgress {
+	return &transferProgress{
+		w:     os.Stdout,
+		tty:   term.IsTerminal(int(os.Stdout.Fd())),
+		lines: make(map[digest.Digest]string),
+	}
+}
+
+// option returns the registry option reporting progress to p.
+func (p *transferProgress) option() registry.Option {
+	return registry.WithProgress(p.handle)
+}
+
+func (p *transferProgress) handle(e registry.Event) {
+	p.stats.Add(e)
+	d := e.Descriptor.Digest
+	id := shortDigest(d)
+
+	if !p.tty {
+		switch e.Kind {
+		case registry.EventDone:
+			fmt.Fprintf(p.w, "  %s: transferred %s\n", id, formatSize(e.Descriptor.Size))
+		case registry.EventSkipped:
+			fmt.Fprintf(p.w, "  %s: already exists\n", id)
+		}
+		return
+	}
+
+	if _, ok := p.lines[d]; !ok {
+		p.blobs = append(p.blobs, d)
+	}
+	switch e.Kind {
+	case registry.EventStart:
+		p.lines[d] = fmt.Sprintf("  %s: waiting", id)
+	case registry.EventProgress:
+		p.lines[d] = fmt.Sprintf("  %s: %s %s / %s", id, progressBar(e.Transferred, e.Descriptor.Size), formatSize(e.Transferred), formatSize(e.Descriptor.Size))
+		// Only the bytes change; don't redraw for every read
+		if time.Since(p.lastDraw) < progressRedrawInterval {
+			return
+		}
+	case registry.EventDone:
+		p.lines[d] = fmt.Sprintf("  %s: done %s", id, formatSize(e.Descriptor.Size))
+	case registry.EventSkipped:
+		p.lines[d] = fmt.Sprintf("  %s: already exists", id)
+	}
+	p.draw()
+}
+
+// draw redraws the progress of every blob, over the previous drawing.
+func (p *transferProgress) draw() {
+	var b strings.Builder
+	if p.drawn > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", p.drawn)
+	}
+	for _, d := range p.blobs {
+		fmt.Fprintf(&b, "\r\x1b[K%s\n", p.lines[d])
+	}
+	fmt.Fprint(p.w, b.String())
+	p.drawn = len(p.blobs)
+	p.lastDraw = time.Now()
+}
+
+// summary returns a summary of the transfer, e.g. "3 blobs transferred (1.20 MB), 2 skipped".
+func (p *transferProgress) summary() string {
+	return fmt.Sprintf("%d blobs transferred (%s), %d already present", p.stats.Transferred, formatSize(p.stats.Bytes), p.stats.Skipped)
+}
+
+// progressBar renders n of total bytes as a bar.
+func progressBar(n, total int64) string {
+	filled := progressBarWidth
+	if total > 0 && n < total {
+		filled = int(n * progressBarWidth / total)
+	}
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled) + "]"
+}
+
+// shortDigest returns the first 12 characters of the encoded digest, like system list.
+func shortDigest(d digest.Digest) string {
+	encoded := d.Encoded()
+	if len(encoded) > 12 {
+		return encoded[:12]
+	}
+	return encoded
+}
@@ -37,12 +37,8 @@ var publishSkillCmd = &cobra.Command{
 			return fmt.Errorf("failed to initialize store: %w", err)
 		}
 
-		// Detect git remote for annotations (reusing logic from build.go which is embedded in store.Build? No, it was injected)
-		// We'll reimplement the simple annotation logic here or refactor into pkg/git later.
-		// For now, let's keep it simple.
-		annotations := map[string]string{
-			"org.opencontainers.image.created": "true", // Placeholder, actual time added by store
-		}
+		// Detect the git source. The skill's own metadata is added by the store.
+		annotations := sourceAnnotations(absPath)
 
 		fmt.Printf("Building skill from %s...\n", srcDir)
 		if err := st.Build(ctx, absPath, tag, annotations); err != nil {
@@ -52,18 +48,19 @@ var publishSkillCmd = &cobra.Command{
 
 		// 2. Push
 		fmt.Printf("Pushing %s...\n", tag)
-
-		if err := registry.Push(ctx, st, tag); err != nil {
+		progress := newTransferProgress()
+		if err := registry.Push(ctx, st, tag, progress.option()); err != nil {
 			return fmt.Errorf("failed to push artifact: %w", err)
 		}
 
-		fmt.Printf("Successfully published %s\n", tag)
+		fmt.Printf("Successfully published %s: %s\n", tag, progress.summary())
 		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(publishSkillCmd)
+	addRegistryFlags(publishSkillCmd)
 	publishSkillCmd.Flags().StringP("tag", "t", "", "Tag for the artifact (required)")
 	publishSkillCmd.MarkFlagRequired("tag")
 }
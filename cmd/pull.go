@@ -26,15 +26,17 @@ content or preparing for offline installation.`,
 		}
 
 		fmt.Printf("Pulling %s...\n", ref)
-		if err := registry.Pull(ctx, st, ref); err != nil {
+		progress := newTransferProgress()
+		if err := registry.Pull(ctx, st, ref, progress.option()); err != nil {
 			return err
 		}
 
-		fmt.Printf("Successfully pulled %s\n", ref)
+		fmt.Printf("Successfully pulled %s: %s\n", ref, progress.summary())
 		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(pullCmd)
+	addRegistryFlags(pullCmd)
 }
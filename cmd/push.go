@@ -26,15 +26,17 @@ available for others to pull and install.`,
 		}
 
 		fmt.Printf("Pushing %s...\n", ref)
-		if err := registry.Push(ctx, st, ref); err != nil {
+		progress := newTransferProgress()
+		if err := registry.Push(ctx, st, ref, progress.option()); err != nil {
 			return err
 		}
 
-		fmt.Printf("Successfully pushed %s\n", ref)
+		fmt.Printf("Successfully pushed %s: %s\n", ref, progress.summary())
 		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(pushCmd)
+	addRegistryFlags(pushCmd)
 }
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"log/slog"
+	"os"
+
+	"github.com/andrewhowdencom/skr/pkg/config"
+	"github.com/andrewhowdencom/skr/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -13,3 +18,53 @@ var registryCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(registryCmd)
 }
+
+// addRegistryFlags adds the flags that configure the connection to every registry to cmd.
+// They take precedence over the registries section of the configuration.
+func addRegistryFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("plain-http", false, "Connect to registries over HTTP instead of HTTPS")
+	cmd.Flags().String("ca-file", "", "PEM file of certificate authorities to trust for registries")
+}
+
+// configureRegistries sets the connection options of registries from the configuration and
+// the registry flags of cmd (see addRegistryFlags).
+func configureRegistries(cmd *cobra.Command) {
+	hosts := make(map[string]registry.HostOptions)
+	if cwd, err := os.Getwd(); err == nil {
+		// Commands that need the configuration report errors loading it themselves
+		if cfg, _, err := loadConfig(cwd, false); err == nil {
+			for host, reg := range cfg.Registries {
+				hosts[host] = hostOptions(reg)
+			}
+		} else {
+			slog.Debug("failed to load registry settings", "error", err)
+		}
+	}
+
+	var defaults registry.HostOptions
+	apply := func(opts *registry.HostOptions) {
+		if f := cmd.Flags().Lookup("plain-http"); f != nil && f.Changed {
+			opts.PlainHTTP, _ = cmd.Flags().GetBool("plain-http")
+		}
+		if f := cmd.Flags().Lookup("ca-file"); f != nil && f.Changed {
+			opts.CAFile, _ = cmd.Flags().GetString("ca-file")
+		}
+	}
+	apply(&defaults)
+	for host, opts := range hosts {
+		apply(&opts)
+		hosts[host] = opts
+	}
+	registry.Configure(defaults, hosts)
+}
+
+// hostOptions returns the connection options of the registry settings reg.
+func hostOptions(reg config.RegistryConfig) registry.HostOptions {
+	return registry.HostOptions{
+		PlainHTTP: reg.PlainHTTP,
+		Insecure:  reg.Insecure,
+		CAFile:    reg.CAFile,
+		CertFile:  reg.CertFile,
+		KeyFile:   reg.KeyFile,
+	}
+}
@@ -20,9 +20,20 @@ This establishes an authenticated session for pushing and pulling private skills
 Credentials are stored locally in the user's config directory.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		server := "ghcr.io" // Default
+		var server string
 		if len(args) > 0 {
 			server = args[0]
+		} else {
+			// Default to the configured registry
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current working directory: %w", err)
+			}
+			cfg, _, err := loadConfig(cwd, false)
+			if err != nil {
+				return err
+			}
+			server = cfg.DefaultRegistryHost()
 		}
 
 		// Read flags
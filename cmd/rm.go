@@ -61,22 +61,30 @@ If --global is set, removes from the global configuration.`,
 		}
 
 		// 2. Remove from Config
-		newSkills := []string{}
+		newSkills := []config.SkillEntry{}
 		removed := false
 		for _, s := range cfg.Skills {
-			if s == ref {
+			if s.Matches(ref) {
 				removed = true
 				continue
 			}
 			// Handle case where ref is "git" but config has "git:v1"?
-			// Ideally we remove exact match or parse.
-			// For now, strict equality.
+			// Entries can set a name to be removed by; otherwise strict equality.
 			newSkills = append(newSkills, s)
 		}
 
 		if removed {
 			cfg.Skills = newSkills
-			if err := cfg.SaveTo(configFilePath); err != nil {
+
+			// Edit the file in place, so that its comments are kept
+			doc, err := config.LoadDocument(configFilePath)
+			if err != nil {
+				return err
+			}
+			if err := doc.Remove("skills", ref); err != nil {
+				return fmt.Errorf("failed to remove skill from config: %w", err)
+			}
+			if err := doc.Save(); err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
 			}
 			slog.Info("removed skill from config", "skill", ref, "config", configFilePath)
@@ -13,6 +13,10 @@ and install skills using standard OCI (Open Container Initiative) registries.
 It simplifies the distribution of AI agent capabilities, treating them as versioned
 artifacts similar to container images.`,
 
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		configureRegistries(cmd)
+	},
+
 	// RunE removed to allow default Cobra behavior (print help)
 	SilenceErrors: true,
 	SilenceUsage:  true,
@@ -24,6 +28,10 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// configPath is an explicit configuration file, which takes precedence over all others.
+var configPath string
+
 func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Configuration file that overrides the system, user and project configuration")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/andrewhowdencom/skr/pkg/skill"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for SKILL.md frontmatter",
+	Long: `Print the JSON Schema that SKILL.md frontmatter is validated against.
+
+Editors with YAML language support can use it to complete and check frontmatter, e.g.:
+
+  skr schema > skill-frontmatter.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(skill.FrontmatterSchema()); err != nil {
+			return fmt.Errorf("failed to print schema: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
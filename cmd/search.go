@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andrewhowdencom/skr/pkg/index"
+	"github.com/andrewhowdencom/skr/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+var searchRegistries []string
+var searchIndexes []string
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search registries for Agent Skills",
+	Long: `Search registries for Agent Skills whose name, description or author contains
+every word of the query.
+
+The default registry and every registry in the configuration are searched. A
+registry with an index configured (registries.<host>.index) is searched through
+that index, as published by 'skr http generate'; other registries are listed
+through their catalog API, which not every registry supports.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		query := strings.Join(args, " ")
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		cfg, _, err := loadConfig(cwd, false)
+		if err != nil {
+			return err
+		}
+
+		hosts := searchRegistries
+		if len(hosts) == 0 {
+			hosts = cfg.RegistryHosts()
+		}
+
+		var indexes []*index.Index
+		for _, host := range hosts {
+			var idx *index.Index
+			if location := cfg.RegistryFor(host).Index; location != "" {
+				idx, err = index.Fetch(ctx, location)
+				if err == nil {
+					// The configured host is where the skills are installed from
+					idx.Registry = host
+				}
+			} else {
+				idx, err = registry.Index(ctx, host)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: cannot search %s: %v\n", host, err)
+				continue
+			}
+			indexes = append(indexes, idx)
+		}
+		for _, location := range searchIndexes {
+			idx, err := index.Fetch(ctx, location)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: cannot search %s: %v\n", location, err)
+				continue
+			}
+			indexes = append(indexes, idx)
+		}
+
+		seen := make(map[string]bool)
+		found := 0
+		for _, idx := range indexes {
+			for _, s := range idx.Search(query) {
+				ref := s.Ref(idx.Registry)
+				if seen[ref] {
+					continue
+				}
+				seen[ref] = true
+				found++
+
+				version := s.Version
+				if version == "" {
+					version = s.Latest
+				}
+				fmt.Printf("%s (%s)\n", s.Name, version)
+				if s.Description != "" {
+					fmt.Printf("  %s\n", s.Description)
+				}
+				if s.Author != "" {
+					fmt.Printf("  Author: %s\n", s.Author)
+				}
+				fmt.Printf("  skr install %s\n\n", ref)
+			}
+		}
+
+		if found == 0 {
+			fmt.Printf("No skills found matching %q\n", query)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().StringSliceVar(&searchRegistries, "registry", nil, "Only search these registries (default: the configured registries)")
+	searchCmd.Flags().StringSliceVar(&searchIndexes, "index", nil, "Also search the index at this URL or path")
+}
@@ -19,31 +19,57 @@ var syncCmd = &cobra.Command{
 	Long: `Synchonize the installed skills in .agent/skills with the declarative list in .skr.yaml.
 
 - Installs skills listed in .skr.yaml that are missing from .agent/skills.
+- Installs the same skills into the skill directory of every agent listed in the config
+  (e.g. .roocode/skills), either as copies or as symlinks to .agent/skills.
 - Removes skills in .agent/skills that are not present in .skr.yaml (unless they are local dependencies/ignored, TBD).
+
+If --global is set, syncs the global configuration into ~/.config/agent/skills and the
+global skill directory of every configured agent.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		isGlobal, _ := cmd.Flags().GetBool("global")
+
 		cwd, err := os.Getwd()
 		if err != nil {
 			return fmt.Errorf("failed to get cwd: %w", err)
 		}
 
-		// 1. Load Config
-		// We expect .skr.yaml to be in the project root.
-		// Discovery logic finds .agent/skills, essentially finding the project root.
-		// So let's find project root first.
-		projectRoot := cwd
-		agentDir, err := discovery.FindAgentSkillsDir(cwd)
-		if err == nil {
-			// If .agent/skills found, assume project root is parent of .agent
-			projectRoot = filepath.Dir(filepath.Dir(agentDir))
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
 		}
-		// If not found, try to load config in cwd anyway, essentially treating cwd as root?
-		// Or if discovery failed, maybe we are initializing?
-		// But sync implies existing structure.
 
-		cfg, err := config.LoadMerged(projectRoot)
-		if err != nil {
-			return err
+		// 1. Load Config
+		var cfg *config.Config
+		var projectRoot string
+		var installRoot string
+
+		if isGlobal {
+			// Global scope only considers the global configuration
+			cfg, _, err = loadConfig(cwd, true)
+			if err != nil {
+				return err
+			}
+			installRoot = filepath.Join(home, ".config", "agent", "skills")
+		} else {
+			// We expect .skr.yaml to be in the project root.
+			// Discovery logic finds .agent/skills, essentially finding the project root.
+			// So let's find project root first.
+			projectRoot = cwd
+			agentDir, err := discovery.FindAgentSkillsDir(cwd)
+			if err == nil {
+				// If .agent/skills found, assume project root is parent of .agent
+				projectRoot = filepath.Dir(filepath.Dir(agentDir))
+			}
+			// If not found, try to load config in cwd anyway, essentially treating cwd as root?
+			// Or if discovery failed, maybe we are initializing?
+			// But sync implies existing structure.
+
+			cfg, _, err = loadConfig(projectRoot, false)
+			if err != nil {
+				return err
+			}
+			installRoot = filepath.Join(projectRoot, ".agent", "skills")
 		}
 
 		if len(cfg.Skills) == 0 {
@@ -52,6 +78,18 @@ var syncCmd = &cobra.Command{
 			return nil
 		}
 
+		mode := cfg.InstallMode
+		if cmd.Flags().Changed("install-mode") {
+			flagMode, _ := cmd.Flags().GetString("install-mode")
+			mode = config.InstallMode(flagMode)
+		}
+
+		// Every configured agent gets the skill set, in addition to the standard location
+		targets, err := cfg.AgentTargets(projectRoot, home, isGlobal)
+		if err != nil {
+			return err
+		}
+
 		// 2. Initialize Store
 		ctx := cmd.Context()
 		st, err := store.New("")
@@ -59,51 +97,76 @@ var syncCmd = &cobra.Command{
 			return fmt.Errorf("failed to initialize store: %w", err)
 		}
 
-		// 3. Ensure .agent/skills exists
-		installRoot := filepath.Join(projectRoot, ".agent", "skills")
+		// 3. Ensure the install root exists
 		if err := os.MkdirAll(installRoot, 0755); err != nil {
 			return fmt.Errorf("failed to create install root %s: %w", installRoot, err)
 		}
 
 		// 4. Install missing skills
-		// Naive implementation: iterate config.Skills, checked if installed.
-		// NOTE: config.Skills might be "git:v1" or just "git".
-		// We need to resolve name.
-		for _, ref := range cfg.Skills {
-			// Check if installed
-			// We need to parse name from ref?
-			// If ref is "git:v1", name is "git".
-			// If ref is "ghcr.io/mypkg/git:latest", name is "git"?
-			// Ideally config should map name -> ref, OR we parse ref.
-			// Simple parsing: last component before tag.
-			// For now, let's assume ref is compatible with what we expect.
-			// Wait, how do we know if it's already installed?
-			// By checking directory existence?
-
-			// FIXME: name resolution is tricky without pulling.
-			// Let's rely on 'install' logic which unpacks to temp and gets name.
-			// For sync, efficiency matters.
-			// IF we mandate ref format <name>:<tag> or just <name>, we can guess.
-			// But for now, let's just attempt install if NOT skipping?
-
-			// Actually, let's call the install logic directly.
-			// Reusing logic from installCmd would be good.
-			// Moving install logic to a pkg/action or similar?
-			// For now, inline or copy/paste logic from install.go.
-
-			slog.Info("syncing skill", "ref", ref)
+		for _, entry := range cfg.Skills {
+			slog.Info("syncing skill", "ref", entry.Ref)
+
+			if err := entry.Validate(); err != nil {
+				return fmt.Errorf("invalid skill entry %s: %w", entry.Ref, err)
+			}
+
+			// Fail closed rather than install a skill that was meant to be verified
+			if entry.Verify != "" {
+				err := fmt.Errorf("skill %s requires signature verification (key %s), which is not supported yet", entry.Ref, entry.Verify)
+				if entry.Optional {
+					slog.Warn("skipping optional skill", "ref", entry.Ref, "error", err)
+					continue
+				}
+				return err
+			}
+
+			// Only the agents the skill is meant for receive it
+			var skillTargets []config.AgentTarget
+			for _, target := range targets {
+				if entry.ForAgent(target.Name) {
+					skillTargets = append(skillTargets, target)
+				}
+			}
 
 			// Install using the action package
-			_, err := action.InstallSkill(ctx, st, ref, installRoot)
+			_, err := action.InstallSkillForAgents(ctx, st, entry.InstallRef(), installRoot, config.TargetDirs(skillTargets), mode)
 			if err != nil {
-				return fmt.Errorf("failed to install %s: %w", ref, err)
+				if entry.Optional {
+					slog.Warn("failed to install optional skill", "ref", entry.Ref, "error", err)
+					continue
+				}
+				return fmt.Errorf("failed to install %s: %w", entry.Ref, err)
 			}
 		}
 
-		return nil
+		// 5. Translate the skill set for agents that need a native format
+		return renderAgents(cfg, targets)
 	},
 }
 
+// renderAgents runs the adapter of each agent over the skills installed in its directory.
+func renderAgents(cfg *config.Config, targets []config.AgentTarget) error {
+	for _, target := range targets {
+		adapter, err := cfg.Adapter(target.Adapter)
+		if err != nil {
+			return fmt.Errorf("agent %s: %w", target.Name, err)
+		}
+
+		set, err := config.LoadSkillSet(target.Dir)
+		if err != nil {
+			return err
+		}
+
+		if err := adapter.Render(set); err != nil {
+			return fmt.Errorf("failed to render skills for agent %s: %w", target.Name, err)
+		}
+	}
+	return nil
+}
+
 func init() {
+	syncCmd.Flags().Bool("global", false, "Sync the global configuration into each agent's global skill directory")
+	syncCmd.Flags().String("install-mode", "", "How skills are placed into additional agent directories (copy, symlink)")
 	rootCmd.AddCommand(syncCmd)
+	addRegistryFlags(syncCmd)
 }
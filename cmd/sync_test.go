@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andrewhowdencom/skr/pkg/action"
+	"github.com/andrewhowdencom/skr/pkg/config"
+	"github.com/andrewhowdencom/skr/pkg/registry"
+	"github.com/andrewhowdencom/skr/pkg/store"
+)
+
+func TestSyncPinnedSkill(t *testing.T) {
+	ctx := context.Background()
+
+	// A registry serving a skill from another store
+	srcDir, src := createTestStore(t)
+	defer os.RemoveAll(srcDir)
+	skillDir := filepath.Join(t.TempDir(), "pdf")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: pdf\ndescription: Read PDF files\n---\n# PDF\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Build(ctx, skillDir, "skills/pdf:v1", nil); err != nil {
+		t.Fatal(err)
+	}
+	desc, err := src.Resolve(ctx, "skills/pdf:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(newOCIHandler(ctx, src, nil))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	registry.Configure(registry.HostOptions{}, map[string]registry.HostOptions{host: {PlainHTTP: true}})
+	defer registry.Configure(registry.HostOptions{}, nil)
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := config.SkillEntry{Ref: host + "/skills/pdf:v1", Pin: desc.Digest.String()}
+	installRoot := filepath.Join(t.TempDir(), ".agent", "skills")
+	if _, err := action.InstallSkillForAgents(ctx, st, entry.InstallRef(), installRoot, nil, config.InstallModeCopy); err != nil {
+		t.Fatalf("failed to install pinned skill: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installRoot, "pdf", "SKILL.md")); err != nil {
+		t.Errorf("expected the skill to be installed: %v", err)
+	}
+
+	// The pinned manifest is tagged by its digest, not "repo@sha256:..."
+	refs, err := st.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := host + "/skills/pdf:" + strings.Replace(desc.Digest.String(), ":", "-", 1)
+	if len(refs) != 1 || refs[0] != want {
+		t.Errorf("got tags %v, want [%s]", refs, want)
+	}
+
+	// Pinned skills are kept by prune, and resolved by their digest
+	if _, _, err := st.Prune(ctx, store.PruneOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := st.Resolve(ctx, entry.InstallRef())
+	if err != nil {
+		t.Fatalf("failed to resolve %s: %v", entry.InstallRef(), err)
+	}
+	if resolved.Digest != desc.Digest {
+		t.Errorf("resolved %s, want %s", resolved.Digest, desc.Digest)
+	}
+	if _, err := st.Fetch(ctx, resolved); err != nil {
+		t.Errorf("pinned skill was pruned: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+var dfVerbose bool
+
+var systemDfCmd = &cobra.Command{
+	Use:   "df",
+	Short: "Show disk usage of the system store",
+	Long: `Show how much space the local system store uses.
+
+Reports the number and size of all blobs, how much 'skr system prune' would
+reclaim, and the size of every tag. Blobs (such as layers) are often shared by
+several tags: SHARED is the part of a tag's size that other tags use as well, and
+UNIQUE is what removing the tag would reclaim.
+
+With -v, the usage of every repository (its tags taken together) is shown too.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := store.New("")
+		if err != nil {
+			return fmt.Errorf("failed to initialize store: %w", err)
+		}
+
+		usage, err := st.Usage(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to compute disk usage: %w", err)
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintf(tw, "Blobs:\t%d\t%s\n", usage.Blobs, formatSize(usage.Size))
+		fmt.Fprintf(tw, "Reclaimable:\t%d\t%s\n", usage.Reclaimable, formatSize(usage.ReclaimableSize))
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if usage.Reclaimable > 0 {
+			fmt.Println("Run 'skr system prune' to reclaim unused space.")
+		}
+
+		fmt.Println()
+		if err := writeUsage(os.Stdout, "TAG", usage.Tags); err != nil {
+			return err
+		}
+
+		if dfVerbose {
+			fmt.Println()
+			if err := writeUsage(os.Stdout, "REPOSITORY", usage.Repositories); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	systemCmd.AddCommand(systemDfCmd)
+	systemDfCmd.Flags().BoolVarP(&dfVerbose, "verbose", "v", false, "Show the usage of every repository")
+}
+
+// writeUsage writes a table of usages, with name as the header of the first column.
+func writeUsage(w io.Writer, name string, usages []store.Usage) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "%s\tBLOBS\tSIZE\tSHARED\tUNIQUE\n", name)
+	for _, u := range usages {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", u.Name, u.Blobs, formatSize(u.Size), formatSize(u.Shared), formatSize(u.Unique()))
+	}
+	return tw.Flush()
+}
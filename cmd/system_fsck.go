@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrewhowdencom/skr/pkg/registry"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// maxRepairRounds bounds how often fsck --repair checks the store again: restoring a
+// manifest can reveal that the blobs it refers to are missing too.
+const maxRepairRounds = 3
+
+var fsckRepair bool
+
+var systemFsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check the integrity of the system store",
+	Long: `Check the integrity of the local system store.
+
+Every blob is re-hashed against its digest, and every tag is walked to check that
+its manifest, config and layers exist. Reports:
+
+  corrupt   a blob whose content does not match its digest
+  missing   a blob that a tagged manifest refers to, but that is not in the store
+  dangling  a tag whose manifest is not in the store
+  orphan    a blob that no tag refers to ('skr system prune' removes these)
+
+With --repair, missing and corrupt blobs are fetched again from the registry of
+the tags they belong to. Skills that were only built locally cannot be repaired;
+build them again instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		// The index may not load if a manifest is corrupt
+		st, err := store.Open("")
+		if err != nil {
+			return fmt.Errorf("failed to initialize store: %w", err)
+		}
+
+		report, err := st.Fsck(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check store: %w", err)
+		}
+
+		for round := 0; fsckRepair && round < maxRepairRounds; round++ {
+			repaired := 0
+			for _, p := range report.Problems {
+				if p.Kind == store.ProblemOrphan || len(p.Refs) == 0 {
+					continue
+				}
+				if err := restoreBlob(cmd, st, p); err != nil {
+					fmt.Printf("Cannot repair %s: %v\n", p.Descriptor.Digest, err)
+					continue
+				}
+				fmt.Printf("Restored %s\n", p.Descriptor.Digest)
+				repaired++
+			}
+			if repaired == 0 {
+				break
+			}
+			if report, err = st.Fsck(ctx); err != nil {
+				return fmt.Errorf("failed to check store: %w", err)
+			}
+		}
+
+		fmt.Printf("Checked %d blobs\n", report.Blobs)
+		errors := 0
+		for _, p := range report.Problems {
+			line := fmt.Sprintf("%-8s  %s", p.Kind, p.Descriptor.Digest)
+			if len(p.Refs) > 0 {
+				line += " (" + strings.Join(p.Refs, ", ") + ")"
+			}
+			fmt.Println(line)
+			if p.Kind != store.ProblemOrphan {
+				errors++
+			}
+		}
+
+		if errors > 0 {
+			if !fsckRepair {
+				fmt.Println("Run 'skr system fsck --repair' to fetch missing and corrupt blobs again.")
+			}
+			return fmt.Errorf("found %d problems in the store", errors)
+		}
+		return nil
+	},
+}
+
+// restoreBlob fetches the blob of p from the registry of any of the tags it belongs to.
+func restoreBlob(cmd *cobra.Command, st *store.Store, p store.Problem) error {
+	var errs []string
+	for _, ref := range p.Refs {
+		err := registry.Restore(cmd.Context(), st, ref, p.Descriptor)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+func init() {
+	systemCmd.AddCommand(systemFsckCmd)
+	systemFsckCmd.Flags().BoolVar(&fsckRepair, "repair", false, "Fetch missing and corrupt blobs again from their registry")
+}
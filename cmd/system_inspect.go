@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"time"
 
 	"github.com/andrewhowdencom/skr/pkg/store"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -54,6 +56,19 @@ Can be used on local artifacts to see details like dependencies, version, and ma
 			return fmt.Errorf("failed to parse manifest: %w", err)
 		}
 
+		if manifest.ArtifactType != "" {
+			fmt.Printf("ArtifactType: %s\n", manifest.ArtifactType)
+		}
+		for _, field := range []struct{ label, key string }{
+			{"Description", ocispec.AnnotationDescription},
+			{"Authors", ocispec.AnnotationAuthors},
+			{"Version", ocispec.AnnotationVersion},
+		} {
+			if v := store.Annotation(manifest.Annotations, field.key); v != "" {
+				fmt.Printf("%s: %s\n", field.label, v)
+			}
+		}
+
 		// Print Annotations
 		if len(manifest.Annotations) > 0 {
 			fmt.Println("\nAnnotations:")
@@ -62,20 +77,50 @@ Can be used on local artifacts to see details like dependencies, version, and ma
 			}
 		}
 
+		// Print Extension Fields
+		if extensionsJSON, ok := manifest.Annotations[store.AnnotationExtensions]; ok {
+			var extensions map[string]any
+			if err := json.Unmarshal([]byte(extensionsJSON), &extensions); err == nil {
+				fmt.Println("\nExtensions:")
+				keys := make([]string, 0, len(extensions))
+				for k := range extensions {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					if value, ok := extensions[k].(string); ok {
+						fmt.Printf("  %s: %s\n", k, value)
+						continue
+					}
+					value, _ := json.Marshal(extensions[k])
+					fmt.Printf("  %s: %s\n", k, value)
+				}
+			}
+		}
+
 		// 3. Fetch Config (if available)
 		fmt.Println("\nConfig:")
 		fmt.Printf("  Digest: %s\n", manifest.Config.Digest)
 		fmt.Printf("  MediaType: %s\n", manifest.Config.MediaType)
 
-		// Optionally fetch config content to show creation time etc.
-		configRc, err := st.Fetch(ctx, manifest.Config)
-		if err == nil {
-			defer configRc.Close()
-			configBytes, _ := io.ReadAll(configRc)
-			var configMap map[string]interface{}
-			if err := json.Unmarshal(configBytes, &configMap); err == nil {
-				if created, ok := configMap["created"]; ok {
-					fmt.Printf("  Created: %v\n", created)
+		if cfg, err := st.SkillConfig(ctx, manifest); err == nil {
+			fmt.Printf("  Created: %s\n", cfg.Created.Format(time.RFC3339))
+			if cfg.Name != "" {
+				fmt.Printf("  Name: %s\n", cfg.Name)
+			}
+			if cfg.Build.Tool != "" {
+				fmt.Printf("  Built with: %s %s\n", cfg.Build.Tool, cfg.Build.ToolVersion)
+			}
+			if cfg.Build.Source != "" {
+				fmt.Printf("  Source: %s\n", cfg.Build.Source)
+			}
+			if cfg.Build.Revision != "" {
+				fmt.Printf("  Revision: %s\n", cfg.Build.Revision)
+			}
+			if len(cfg.Files) > 0 {
+				fmt.Printf("  Files: %d\n", len(cfg.Files))
+				for _, f := range cfg.Files {
+					fmt.Printf("    %s %s %s\n", f.Mode, f.Type, f.Path)
 				}
 			}
 		}
@@ -1,19 +1,48 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
 
 	"github.com/andrewhowdencom/skr/pkg/store"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listFilters []string
+	listSort    string
+	listDigests bool
+	listFormat  string
+)
+
 var systemListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List built/pulled artifacts in Local Registry",
 	Long: `List all skill artifacts stored in the local OCI registry.
-	
-Shows repository, tag, digest, and size.`,
+
+Shows repository, tag, skill name, creation time, total size (manifest, config and
+layers), and the other tags of the same digest. Skills that are no longer tagged are
+listed as dangling (<none>) until 'skr system prune' removes them.
+
+Filters (--filter, may be repeated):
+  <glob>, reference=<glob>  repository (or repository:tag) matches the glob
+  before=<ref|time>         built before the given tag or time (RFC 3339 or YYYY-MM-DD)
+  since=<ref|time>          built after the given tag or time
+  dangling=<true|false>     only (or no) dangling skills
+
+--format takes "table", "json", or a Go template executed for every artifact, with
+the fields of the JSON output: {{.Repository}}, {{.Tag}}, {{.Digest}}, {{.Name}},
+{{.Created}}, {{.Size}}, {{.Dangling}} and {{.Tags}}.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		st, err := store.New("")
@@ -21,54 +50,316 @@ Shows repository, tag, digest, and size.`,
 			return fmt.Errorf("failed to initialize store: %w", err)
 		}
 
-		tags, err := st.List(ctx)
+		artifacts, err := st.Artifacts(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to list skills: %w", err)
 		}
 
-		// Header
-		fmt.Printf("%-30s %-15s %-15s %-10s\n", "REPOSITORY", "TAG", "IMAGE ID", "SIZE")
+		var items []listItem
+		for _, a := range artifacts {
+			if a.Err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v (see 'skr system fsck')\n", a.Ref, a.Err)
+				continue
+			}
+			items = append(items, newListItem(a))
+		}
+		setListTags(items)
+
+		keep, err := parseListFilters(listFilters, items)
+		if err != nil {
+			return err
+		}
+		var filtered []listItem
+		for _, item := range items {
+			if keep(item) {
+				filtered = append(filtered, item)
+			}
+		}
+
+		if err := sortListItems(filtered, listSort); err != nil {
+			return err
+		}
+		return writeListItems(os.Stdout, listFormat, listDigests, filtered)
+	},
+}
+
+func init() {
+	systemCmd.AddCommand(systemListCmd)
+	systemListCmd.Flags().StringArrayVarP(&listFilters, "filter", "f", nil, "Filter the artifacts (e.g. 'ghcr.io/acme/*', 'since=2024-01-01', 'dangling=true')")
+	systemListCmd.Flags().StringVar(&listSort, "sort", "repository", "Sort by repository, name, created (newest first) or size (largest first)")
+	systemListCmd.Flags().BoolVar(&listDigests, "digests", false, "Show the full digests")
+	systemListCmd.Flags().StringVar(&listFormat, "format", "table", "Output format: table, json, or a Go template")
+}
+
+// listItem is an artifact as shown by system list.
+type listItem struct {
+	Repository string    `json:"repository"`
+	Tag        string    `json:"tag"`
+	Digest     string    `json:"digest"`
+	Name       string    `json:"name"`
+	Created    time.Time `json:"created,omitzero"`
+	Size       int64     `json:"size"`
+	Dangling   bool      `json:"dangling"`
+	// Tags are all the tags of Digest.
+	Tags []string `json:"tags"`
+}
+
+func newListItem(a store.Artifact) listItem {
+	item := listItem{
+		Repository: "<none>",
+		Tag:        "<none>",
+		Digest:     a.Descriptor.Digest.String(),
+		Name:       a.Name,
+		Created:    a.Created,
+		Size:       a.Size,
+		Dangling:   a.Dangling,
+	}
+	if a.Ref != "" {
+		item.Repository = a.Ref
+		// For REPOSITORY/TAG splitting, we assume standard "repo:tag" format.
+		if idx := strings.LastIndex(a.Ref, ":"); idx != -1 && !strings.Contains(a.Ref[idx:], "/") {
+			item.Repository, item.Tag = a.Ref[:idx], a.Ref[idx+1:]
+		}
+	}
+	return item
+}
+
+// ref returns the tag of the item, as given on the command line.
+func (i listItem) ref() string {
+	if i.Dangling {
+		return ""
+	}
+	if i.Tag == "<none>" {
+		return i.Repository
+	}
+	return i.Repository + ":" + i.Tag
+}
+
+// setListTags sets the Tags of every item.
+func setListTags(items []listItem) {
+	tagsOf := make(map[string][]string)
+	for _, item := range items {
+		if ref := item.ref(); ref != "" {
+			tagsOf[item.Digest] = append(tagsOf[item.Digest], ref)
+		}
+	}
+	for i := range items {
+		items[i].Tags = tagsOf[items[i].Digest]
+		if items[i].Tags == nil {
+			items[i].Tags = []string{}
+		}
+	}
+}
+
+// parseListFilters returns a function that reports whether an item matches all filters.
+// before= and since= may refer to the tags of items.
+func parseListFilters(filters []string, items []listItem) (func(listItem) bool, error) {
+	var patterns []string
+	var checks []func(listItem) bool
+
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			key, value = "reference", filter
+		}
 
-		for _, tag := range tags {
-			// Resolve to get digest and size
-			desc, err := st.Resolve(ctx, tag)
+		switch key {
+		case "reference":
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
+			}
+			patterns = append(patterns, value)
+		case "before", "since":
+			t, err := parseListTime(value, items)
 			if err != nil {
-				// warn and continue?
-				continue
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
 			}
+			if key == "before" {
+				checks = append(checks, func(i listItem) bool { return !i.Created.IsZero() && i.Created.Before(t) })
+			} else {
+				checks = append(checks, func(i listItem) bool { return i.Created.After(t) })
+			}
+		case "dangling":
+			dangling, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
+			}
+			checks = append(checks, func(i listItem) bool { return i.Dangling == dangling })
+		default:
+			return nil, fmt.Errorf("unknown filter %q: use reference, before, since or dangling", key)
+		}
+	}
 
-			// For REPOSITORY/TAG splitting, we assume standard "repo:tag" format.
-			repo := tag
-			version := "<none>"
+	// An item matches if it matches any of the patterns
+	if len(patterns) > 0 {
+		checks = append(checks, func(i listItem) bool {
+			if i.Dangling {
+				return false
+			}
+			for _, pattern := range patterns {
+				if ok, _ := path.Match(pattern, i.Repository); ok {
+					return true
+				}
+				if ok, _ := path.Match(pattern, i.ref()); ok {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	return func(i listItem) bool {
+		for _, check := range checks {
+			if !check(i) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// parseListTime parses value as a time, or as the tag of one of items.
+func parseListTime(value string, items []listItem) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	for _, item := range items {
+		if item.ref() == value {
+			if item.Created.IsZero() {
+				return time.Time{}, fmt.Errorf("creation time of %s is unknown", value)
+			}
+			return item.Created, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s is neither a time nor a tag in the store", value)
+}
 
-			if idx := lastIndex(tag, ":"); idx != -1 {
-				repo = tag[:idx]
-				version = tag[idx+1:]
+// sortListItems sorts items by key.
+func sortListItems(items []listItem, key string) error {
+	var less func(a, b listItem) bool
+	switch key {
+	case "repository":
+		less = func(a, b listItem) bool {
+			if a.Dangling != b.Dangling {
+				return b.Dangling
+			}
+			if a.Repository != b.Repository {
+				return a.Repository < b.Repository
 			}
+			return a.Tag < b.Tag
+		}
+	case "name":
+		less = func(a, b listItem) bool { return a.Name < b.Name }
+	case "created":
+		less = func(a, b listItem) bool { return a.Created.After(b.Created) }
+	case "size":
+		less = func(a, b listItem) bool { return a.Size > b.Size }
+	default:
+		return fmt.Errorf("unknown sort key %q: use repository, name, created or size", key)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+	return nil
+}
 
-			// Short digest
-			digestVal := desc.Digest.String()
-			if len(digestVal) > 12 {
-				digestVal = digestVal[7:19] // sha256:1234... -> 1234...
+// writeListItems writes items in format.
+func writeListItems(w io.Writer, format string, digests bool, items []listItem) error {
+	switch format {
+	case "json":
+		if items == nil {
+			items = []listItem{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+
+	case "table", "":
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		if digests {
+			fmt.Fprintln(tw, "REPOSITORY\tTAG\tDIGEST\tNAME\tCREATED\tSIZE\tALSO TAGGED")
+		} else {
+			fmt.Fprintln(tw, "REPOSITORY\tTAG\tIMAGE ID\tNAME\tCREATED\tSIZE\tALSO TAGGED")
+		}
+
+		now := time.Now()
+		for _, item := range items {
+			id := item.Digest
+			if !digests {
+				// sha256:1234... -> 1234...
+				if _, encoded, ok := strings.Cut(id, ":"); ok && len(encoded) > 12 {
+					id = encoded[:12]
+				}
 			}
 
-			// Size (human readable-ish)
-			size := fmt.Sprintf("%d B", desc.Size)
-			if desc.Size > 1024 {
-				size = fmt.Sprintf("%.2f KB", float64(desc.Size)/1024)
+			var others []string
+			for _, tag := range item.Tags {
+				if tag != item.ref() {
+					others = append(others, tag)
+				}
+			}
+			also := "-"
+			if len(others) > 0 {
+				also = strings.Join(others, ", ")
 			}
 
-			fmt.Printf("%-30s %-15s %-15s %-10s\n", repo, version, digestVal, size)
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", item.Repository, item.Tag, id, item.Name, formatAge(item.Created, now), formatSize(item.Size), also)
 		}
+		return tw.Flush()
 
+	default:
+		tmpl, err := template.New("format").Parse(format)
+		if err != nil {
+			return fmt.Errorf("invalid format: %w", err)
+		}
+		for _, item := range items {
+			if err := tmpl.Execute(w, item); err != nil {
+				return fmt.Errorf("failed to format %s: %w", item.Digest, err)
+			}
+			fmt.Fprintln(w)
+		}
 		return nil
-	},
+	}
 }
 
-func init() {
-	systemCmd.AddCommand(systemListCmd)
+// formatAge returns how long ago t was, e.g. "3 days ago".
+func formatAge(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	age := now.Sub(t)
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age.Minutes()), "minute")
+	case age < 24*time.Hour:
+		return plural(int(age.Hours()), "hour")
+	case age < 30*24*time.Hour:
+		return plural(int(age.Hours()/24), "day")
+	case age < 365*24*time.Hour:
+		return plural(int(age.Hours()/24/30), "month")
+	default:
+		return plural(int(age.Hours()/24/365), "year")
+	}
 }
 
-func lastIndex(s, sep string) int {
-	return strings.LastIndex(s, sep)
+// formatSize returns n bytes in human readable units.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1024*1024*1024:
+		return fmt.Sprintf("%.2f GB", float64(n)/(1024*1024*1024))
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.2f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.2f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
 }
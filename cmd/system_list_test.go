@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListFilters(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	items := []listItem{
+		{Repository: "ghcr.io/acme/pdf", Tag: "v1", Name: "pdf", Created: day(1), Size: 300},
+		{Repository: "ghcr.io/acme/pdf", Tag: "v2", Name: "pdf", Created: day(3), Size: 100},
+		{Repository: "localhost/docx", Tag: "latest", Name: "docx", Created: day(2), Size: 200},
+		{Repository: "<none>", Tag: "<none>", Name: "docx", Created: day(1), Dangling: true},
+	}
+
+	tests := []struct {
+		filters []string
+		want    []string
+	}{
+		{nil, []string{"ghcr.io/acme/pdf:v1", "ghcr.io/acme/pdf:v2", "localhost/docx:latest", ""}},
+		{[]string{"ghcr.io/acme/*"}, []string{"ghcr.io/acme/pdf:v1", "ghcr.io/acme/pdf:v2"}},
+		{[]string{"reference=*/*/pdf:v2", "localhost/*"}, []string{"ghcr.io/acme/pdf:v2", "localhost/docx:latest"}},
+		{[]string{"since=localhost/docx:latest"}, []string{"ghcr.io/acme/pdf:v2"}},
+		{[]string{"before=2024-01-02", "dangling=false"}, []string{"ghcr.io/acme/pdf:v1"}},
+		{[]string{"dangling=true"}, []string{""}},
+	}
+	for _, tt := range tests {
+		keep, err := parseListFilters(tt.filters, items)
+		if err != nil {
+			t.Fatalf("parseListFilters(%v): %v", tt.filters, err)
+		}
+		var got []string
+		for _, item := range items {
+			if keep(item) {
+				got = append(got, item.ref())
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("filters %v: got %v, want %v", tt.filters, got, tt.want)
+		}
+	}
+
+	for _, filter := range []string{"since=localhost/missing:v1", "dangling=maybe", "size=1", "["} {
+		if _, err := parseListFilters([]string{filter}, items); err == nil {
+			t.Errorf("filter %q: expected an error", filter)
+		}
+	}
+
+	sorted := append([]listItem(nil), items...)
+	if err := sortListItems(sorted, "size"); err != nil {
+		t.Fatal(err)
+	}
+	if sorted[0].Size != 300 || sorted[3].Size != 0 {
+		t.Errorf("unexpected order by size: %v", sorted)
+	}
+	if err := sortListItems(sorted, "created"); err != nil {
+		t.Fatal(err)
+	}
+	if !sorted[0].Created.Equal(day(3)) {
+		t.Errorf("unexpected order by creation time: %v", sorted)
+	}
+	if err := sortListItems(sorted, "bogus"); err == nil {
+		t.Error("expected an error for an unknown sort key")
+	}
+}
+
+func TestWriteListItems(t *testing.T) {
+	items := []listItem{{Repository: "localhost/docx", Tag: "latest", Digest: "sha256:0123456789abcdef", Size: 2048, Tags: []string{"localhost/docx:latest"}}}
+
+	var buf bytes.Buffer
+	if err := writeListItems(&buf, "{{.Repository}}:{{.Tag}} {{.Size}}", false, items); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "localhost/docx:latest 2048\n" {
+		t.Errorf("unexpected template output: %q", got)
+	}
+
+	buf.Reset()
+	if err := writeListItems(&buf, "json", false, items); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0]["digest"] != "sha256:0123456789abcdef" {
+		t.Errorf("unexpected JSON output: %s", buf.String())
+	}
+	if _, ok := decoded[0]["created"]; ok {
+		t.Error("unknown creation time should be omitted")
+	}
+
+	buf.Reset()
+	if err := writeListItems(&buf, "table", false, items); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "0123456789ab ") || !strings.Contains(buf.String(), "2.00 KB") {
+		t.Errorf("unexpected table output: %s", buf.String())
+	}
+
+	if err := writeListItems(&buf, "{{.Bogus", false, items); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
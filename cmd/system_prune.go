@@ -2,15 +2,27 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/andrewhowdencom/skr/pkg/store"
 	"github.com/spf13/cobra"
 )
 
+var pruneDryRun bool
+var pruneOlderThan time.Duration
+
 var systemPruneCmd = &cobra.Command{
 	Use:   "prune",
 	Short: "Remove unused data",
-	Long:  `Remove unused data (blob garbage collection). This command deletes any content in the local store that is not referenced by any tag.`,
+	Long: `Remove unused data (blob garbage collection). This command deletes any content in the local store that is not referenced by any tag.
+
+Content is kept if it can be reached from a tag: the manifest, its config and
+layers, the manifests of an image index, and any artifact (such as a signature
+or SBOM) that refers to reachable content as its subject.
+
+The store is locked while pruning, so content written by a build that is still
+running is never removed. Use --older-than to also keep recent content written
+by other tools.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		st, err := store.New("")
 		if err != nil {
@@ -18,12 +30,20 @@ var systemPruneCmd = &cobra.Command{
 		}
 
 		ctx := cmd.Context()
-		count, size, err := st.Prune(ctx)
+		count, size, err := st.Prune(ctx, store.PruneOptions{
+			DryRun:    pruneDryRun,
+			OlderThan: pruneOlderThan,
+		})
 		if err != nil {
 			return fmt.Errorf("failed to prune system: %w", err)
 		}
 
-		fmt.Printf("Deleted %d artifacts\n", count)
+		if pruneDryRun {
+			fmt.Printf("Would delete %d blobs\n", count)
+			fmt.Printf("Would reclaim %.2f MB\n", float64(size)/1024/1024)
+			return nil
+		}
+		fmt.Printf("Deleted %d blobs\n", count)
 		fmt.Printf("Reclaimed %.2f MB\n", float64(size)/1024/1024)
 
 		return nil
@@ -32,4 +52,6 @@ var systemPruneCmd = &cobra.Command{
 
 func init() {
 	systemCmd.AddCommand(systemPruneCmd)
+	systemPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be deleted, without deleting it")
+	systemPruneCmd.Flags().DurationVar(&pruneOlderThan, "older-than", 0, "Only delete content last written longer ago than this (e.g. 24h)")
 }
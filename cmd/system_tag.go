@@ -3,20 +3,57 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/andrewhowdencom/skr/pkg/config"
+	"github.com/andrewhowdencom/skr/pkg/store"
 	"github.com/spf13/cobra"
 )
 
-var tagCmd = &cobra.Command{
-	Use:   "tag",
+var systemTagCmd = &cobra.Command{
+	Use:   "tag <source> <target>...",
 	Short: "Create a tag for a local Agent Skill",
 	Long: `Tag a local Agent Skill image with a new name and/or tag.
 
-This is mostly used to prepare a built skill for pushing to a specific registry.`,
+This is mostly used to prepare a built skill for pushing to a specific registry:
+
+  skr system tag my-skill:1.0.0 ghcr.io/acme/my-skill:1.0.0 ghcr.io/acme/my-skill:latest
+
+The source may be a tag or a digest. Targets without a tag are tagged "latest".
+Existing targets are moved to the source.`,
+	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return fmt.Errorf("not yet implemented")
+		ctx := cmd.Context()
+
+		targets := make([]string, 0, len(args)-1)
+		for _, target := range args[1:] {
+			if err := config.ValidateRef(target); err != nil {
+				return err
+			}
+			if config.Repository(target) == target {
+				target += ":latest"
+			}
+			targets = append(targets, target)
+		}
+
+		st, err := store.New("")
+		if err != nil {
+			return fmt.Errorf("failed to initialize store: %w", err)
+		}
+
+		desc, err := st.Resolve(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve reference %s: %w", args[0], err)
+		}
+
+		for _, target := range targets {
+			if err := st.Tag(ctx, desc, target); err != nil {
+				return fmt.Errorf("failed to tag %s: %w", target, err)
+			}
+			fmt.Printf("Tagged %s as %s\n", args[0], target)
+		}
+		return nil
 	},
 }
 
 func init() {
-	systemCmd.AddCommand(tagCmd)
+	systemCmd.AddCommand(systemTagCmd)
 }
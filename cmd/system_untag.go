@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+var systemUntagCmd = &cobra.Command{
+	Use:   "untag <ref>...",
+	Short: "Remove tags from the system store",
+	Long: `Remove one or more tags from the local system store.
+
+Unlike 'skr system rm', which removes a skill along with all of its tags, untag only
+removes the given tag. The skill is deleted once its last tag is removed.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		st, err := store.New("")
+		if err != nil {
+			return fmt.Errorf("failed to initialize store: %w", err)
+		}
+
+		var errs []error
+		for _, ref := range args {
+			desc, err := st.Resolve(ctx, ref)
+			if err != nil {
+				err := fmt.Errorf("failed to resolve reference %s: %w", ref, err)
+				fmt.Println(err)
+				errs = append(errs, err)
+				continue
+			}
+
+			deleted, err := st.Untag(ctx, ref)
+			if err != nil {
+				err := fmt.Errorf("failed to untag %s: %w", ref, err)
+				fmt.Println(err)
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Printf("Untagged %s\n", ref)
+			if deleted {
+				fmt.Printf("Deleted %s\n", desc.Digest)
+			}
+		}
+
+		if len(errs) > 0 {
+			return fmt.Errorf("failed to untag some artifacts")
+		}
+		return nil
+	},
+}
+
+func init() {
+	systemCmd.AddCommand(systemUntagCmd)
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/andrewhowdencom/skr/pkg/skill"
+	"github.com/andrewhowdencom/skr/pkg/lint"
 	"github.com/spf13/cobra"
 )
 
@@ -12,11 +15,10 @@ var validateCmd = &cobra.Command{
 	Short: "Validate an Agent Skill definition",
 	Long: `Validate an Agent Skill's integrity and adherence to the specification.
 
-Checks for:
-- Existence of SKILL.md
-- Valid frontmatter
-- Spec compliance (naming, fields)
-- Directory structure
+Runs the following lint rules:
+` + ruleList() + `
+Rules can be disabled with --disable, or with lint.disable in the configuration.
+Fails if any rule reports an error; warnings are only reported.
 
 If [path] is not provided, defaults to the current directory.`,
 	Args: cobra.MaximumNArgs(1),
@@ -25,17 +27,48 @@ If [path] is not provided, defaults to the current directory.`,
 		if len(args) > 0 {
 			path = args[0]
 		}
+		format, _ := cmd.Flags().GetString("format")
+		disable, _ := cmd.Flags().GetStringSlice("disable")
 
-		s, err := skill.Load(path)
+		absPath, err := filepath.Abs(path)
 		if err != nil {
-			return fmt.Errorf("skill is invalid: %w", err)
+			return fmt.Errorf("failed to resolve %s: %w", path, err)
+		}
+		cfg, _, err := loadConfig(absPath, false)
+		if err != nil {
+			return err
+		}
+
+		findings, err := lint.Lint(path, lint.Options{Disable: append(cfg.Lint.Disable, disable...)})
+		if err != nil {
+			return err
+		}
+
+		if err := lint.Write(os.Stdout, format, path, findings); err != nil {
+			return err
+		}
+
+		if lint.HasErrors(findings) {
+			return fmt.Errorf("skill is invalid")
 		}
 
-		fmt.Printf("Skill '%s' is valid.\n", s.Name)
+		if format == lint.FormatText {
+			fmt.Printf("Skill at %s is valid.\n", path)
+		}
 		return nil
 	},
 }
 
+func ruleList() string {
+	var b strings.Builder
+	for _, r := range lint.Rules {
+		fmt.Fprintf(&b, "- %s (%s): %s\n", r.ID, r.Severity, r.Description)
+	}
+	return b.String()
+}
+
 func init() {
 	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().String("format", lint.FormatText, "Output format: text, json or sarif")
+	validateCmd.Flags().StringSlice("disable", nil, "IDs of lint rules to skip")
 }
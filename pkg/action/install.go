@@ -9,7 +9,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 
+	"github.com/andrewhowdencom/skr/pkg/config"
 	"github.com/andrewhowdencom/skr/pkg/registry"
 	"github.com/andrewhowdencom/skr/pkg/resolution"
 	"github.com/andrewhowdencom/skr/pkg/skill"
@@ -19,7 +22,65 @@ import (
 
 // InstallSkill installs a skill and its dependencies from the store to the installDir.
 func InstallSkill(ctx context.Context, st *store.Store, ref, installDir string) (string, error) {
-	// 1. Resolve all dependencies
+	names, err := installAll(ctx, st, ref, installDir)
+	if err != nil {
+		return "", err
+	}
+	return names[0], nil
+}
+
+// InstallSkillForAgents installs a skill and its dependencies into primaryDir, then mirrors
+// every installed skill into each of agentDirs. Depending on mode, the mirrors are either
+// full copies or symlinks to the copy in primaryDir.
+func InstallSkillForAgents(ctx context.Context, st *store.Store, ref, primaryDir string, agentDirs []string, mode config.InstallMode) (string, error) {
+	names, err := installAll(ctx, st, ref, primaryDir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, dir := range agentDirs {
+		if filepath.Clean(dir) == filepath.Clean(primaryDir) {
+			continue
+		}
+		for _, name := range names {
+			if err := MirrorSkill(filepath.Join(primaryDir, name), dir, mode); err != nil {
+				return "", fmt.Errorf("failed to install %s for agent directory %s: %w", name, dir, err)
+			}
+		}
+	}
+
+	return names[0], nil
+}
+
+// MirrorSkill places the installed skill at srcPath into agentDir, replacing any existing copy.
+func MirrorSkill(srcPath, agentDir string, mode config.InstallMode) error {
+	if err := os.MkdirAll(agentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create agent directory: %w", err)
+	}
+
+	targetPath := filepath.Join(agentDir, filepath.Base(srcPath))
+	if err := os.RemoveAll(targetPath); err != nil {
+		return fmt.Errorf("failed to remove existing skill at %s: %w", targetPath, err)
+	}
+
+	switch mode {
+	case config.InstallModeSymlink:
+		// Prefer relative links so the project can be moved around
+		link, err := filepath.Rel(agentDir, srcPath)
+		if err != nil {
+			link = srcPath
+		}
+		return os.Symlink(link, targetPath)
+	case config.InstallModeCopy, "":
+		return copyDir(srcPath, targetPath)
+	default:
+		return fmt.Errorf("unknown install mode %q", mode)
+	}
+}
+
+// installAll installs ref and its dependencies into installDir, returning the names of the
+// installed skills with the root skill first.
+func installAll(ctx context.Context, st *store.Store, ref, installDir string) ([]string, error) {
 	// 1. Resolve all dependencies
 	resolver := resolution.New(st)
 	resolver.SetPuller(func(ctx context.Context, ref string) error {
@@ -29,25 +90,21 @@ func InstallSkill(ctx context.Context, st *store.Store, ref, installDir string)
 
 	refs, err := resolver.Resolve(ctx, ref)
 	if err != nil {
-		return "", fmt.Errorf("failed to resolve dependencies for %s: %w", ref, err)
+		return nil, fmt.Errorf("failed to resolve dependencies for %s: %w", ref, err)
 	}
 
-	var rootName string
-
 	// 2. Install each skill (sequentially for now)
-	for i, r := range refs {
+	// The first one in the resolved list is the root skill (BFS start)
+	var names []string
+	for _, r := range refs {
 		name, err := installOne(ctx, st, r, installDir)
 		if err != nil {
-			return "", fmt.Errorf("failed to install %s: %w", r, err)
-		}
-
-		// The first one in the resolved list is the root skill (BFS start)
-		if i == 0 {
-			rootName = name
+			return nil, fmt.Errorf("failed to install %s: %w", r, err)
 		}
+		names = append(names, name)
 	}
 
-	return rootName, nil
+	return names, nil
 }
 
 func installOne(ctx context.Context, st *store.Store, ref, installDir string) (string, error) {
@@ -170,8 +227,16 @@ func unpackLayer(r io.Reader, dest string) error {
 	}
 	defer gzr.Close()
 
+	root, err := filepath.EvalSymlinks(dest)
+	if err != nil {
+		return err
+	}
+
 	tr := tar.NewReader(gzr)
 
+	// Track symlinks so that no entry is ever written through one.
+	symlinks := make(map[string]bool)
+
 	for {
 		header, err := tr.Next()
 		if err == io.EOF {
@@ -187,13 +252,32 @@ func unpackLayer(r io.Reader, dest string) error {
 			return fmt.Errorf("tar archive contains unsafe filename: %s", header.Name)
 		}
 
+		for parent := filepath.Dir(filepath.Clean(header.Name)); parent != "."; parent = filepath.Dir(parent) {
+			if symlinks[parent] {
+				return fmt.Errorf("tar archive contains entry %s beneath symlink %s", header.Name, parent)
+			}
+		}
+
+		// Never replace what is already there: an existing symlink would be followed
+		if fi, err := os.Lstat(target); err == nil {
+			if fi.Mode()&os.ModeSymlink != 0 || header.Typeflag != tar.TypeDir || !fi.IsDir() {
+				return fmt.Errorf("tar archive contains duplicate entry %s", header.Name)
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+
 		switch header.Typeflag {
 		case tar.TypeDir:
 			if err := os.MkdirAll(target, 0755); err != nil {
 				return err
 			}
 		case tar.TypeReg:
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			mode := header.FileInfo().Mode().Perm()
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY|syscall.O_NOFOLLOW, mode)
 			if err != nil {
 				return err
 			}
@@ -201,12 +285,106 @@ func unpackLayer(r io.Reader, dest string) error {
 				f.Close()
 				return err
 			}
-			f.Close()
+			// Apply the mode explicitly so the umask does not strip executable bits
+			if err := f.Chmod(mode); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := skill.CheckLink(header.Name, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			// The target may go through links extracted before, so resolve it on disk
+			dir, err := filepath.EvalSymlinks(filepath.Dir(target))
+			if err != nil {
+				return err
+			}
+			resolved, err := resolveLink(dir, header.Linkname)
+			if err != nil {
+				return fmt.Errorf("failed to resolve symlink %s: %w", header.Name, err)
+			}
+			if rel, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(rel) {
+				return fmt.Errorf("symlink %s points outside the skill directory", header.Name)
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+			symlinks[filepath.Clean(header.Name)] = true
+		}
+	}
+
+	// A link can also be redirected by links extracted after it, so check where every
+	// link finally lands.
+	for name := range symlinks {
+		resolved, err := filepath.EvalSymlinks(filepath.Join(dest, name))
+		if err != nil {
+			// Dangling links cannot escape
+			continue
+		}
+		if rel, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(rel) {
+			return fmt.Errorf("symlink %s points outside the skill directory", name)
 		}
 	}
+
 	return nil
 }
 
+// maxLinkHops bounds the symlinks followed by resolveLink, like the kernel's ELOOP limit.
+const maxLinkHops = 40
+
+// resolveLink returns the path the relative symlink target in the directory dir points to,
+// following the links on disk one component at a time. Components that do not exist yet
+// are taken as they are.
+func resolveLink(dir, target string) (string, error) {
+	path := dir
+	components := strings.Split(filepath.ToSlash(target), "/")
+	hops := 0
+	for len(components) > 0 {
+		c := components[0]
+		components = components[1:]
+		switch c {
+		case "", ".":
+			continue
+		case "..":
+			path = filepath.Dir(path)
+			continue
+		}
+
+		next := filepath.Join(path, c)
+		fi, err := os.Lstat(next)
+		if os.IsNotExist(err) {
+			path = next
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			path = next
+			continue
+		}
+
+		if hops++; hops > maxLinkHops {
+			return "", fmt.Errorf("too many levels of symbolic links")
+		}
+		link, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(link) {
+			path = string(filepath.Separator)
+		}
+		components = append(strings.Split(filepath.ToSlash(link), "/"), components...)
+	}
+	return path, nil
+}
+
 func copyDir(src, dst string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -219,6 +397,14 @@ func copyDir(src, dst string) error {
 		}
 		targetPath := filepath.Join(dst, relPath)
 
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, targetPath)
+		}
+
 		if info.IsDir() {
 			return os.MkdirAll(targetPath, info.Mode())
 		}
@@ -0,0 +1,173 @@
+package action
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andrewhowdencom/skr/pkg/config"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallSkill_PreservesModesAndSymlinks(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup a skill with a script and a symlink
+	srcDir := t.TempDir()
+	content := "---\nname: linked-skill\ndescription: test skill\n---\n# Linked\n"
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "scripts", "run.sh"), []byte("#!/bin/sh\necho hi\n"), 0755))
+	require.NoError(t, os.Symlink("scripts/run.sh", filepath.Join(srcDir, "run")))
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+
+	ref := "example.com/linked-skill:v1"
+	require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+
+	// Check the layer annotations
+	desc, err := st.Resolve(ctx, ref)
+	require.NoError(t, err)
+	rc, err := st.Fetch(ctx, desc)
+	require.NoError(t, err)
+	manifestBytes, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(manifestBytes, &manifest))
+	require.Len(t, manifest.Layers, 1)
+	assert.JSONEq(t, `["scripts/run.sh"]`, manifest.Layers[0].Annotations[store.AnnotationExecutables])
+	assert.JSONEq(t, `{"run":"scripts/run.sh"}`, manifest.Layers[0].Annotations[store.AnnotationSymlinks])
+
+	// Install and verify
+	installDir := t.TempDir()
+	name, err := InstallSkill(ctx, st, ref, installDir)
+	require.NoError(t, err)
+	assert.Equal(t, "linked-skill", name)
+
+	info, err := os.Stat(filepath.Join(installDir, name, "scripts", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(installDir, name, "run"))
+	require.NoError(t, err)
+	assert.Equal(t, "scripts/run.sh", link)
+}
+
+func TestBuild_RejectsEscapingSymlink(t *testing.T) {
+	srcDir := t.TempDir()
+	content := "---\nname: escaping-skill\ndescription: test skill\n---\n"
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+	require.NoError(t, os.Symlink("../../etc/passwd", filepath.Join(srcDir, "passwd")))
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+
+	err = st.Build(context.Background(), srcDir, "example.com/escaping-skill:v1", nil)
+	assert.ErrorContains(t, err, "outside the skill directory")
+}
+
+func TestInstallSkillForAgents(t *testing.T) {
+	ctx := context.Background()
+
+	srcDir := t.TempDir()
+	content := "---\nname: shared-skill\ndescription: test skill\n---\n# Shared\n"
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+
+	ref := "example.com/shared-skill:v1"
+	require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+
+	projectRoot := t.TempDir()
+	primaryDir := filepath.Join(projectRoot, ".agent", "skills")
+	linkedDir := filepath.Join(projectRoot, ".roocode", "skills")
+	copiedDir := filepath.Join(projectRoot, ".other", "skills")
+
+	// Symlink mode
+	_, err = InstallSkillForAgents(ctx, st, ref, primaryDir, []string{primaryDir, linkedDir}, config.InstallModeSymlink)
+	require.NoError(t, err)
+
+	link, err := os.Readlink(filepath.Join(linkedDir, "shared-skill"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("..", "..", ".agent", "skills", "shared-skill"), link)
+	assert.FileExists(t, filepath.Join(linkedDir, "shared-skill", "SKILL.md"))
+
+	// Copy mode
+	_, err = InstallSkillForAgents(ctx, st, ref, primaryDir, []string{copiedDir}, config.InstallModeCopy)
+	require.NoError(t, err)
+
+	info, err := os.Lstat(filepath.Join(copiedDir, "shared-skill"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.FileExists(t, filepath.Join(copiedDir, "shared-skill", "SKILL.md"))
+}
+
+// tarEntry is an entry of a layer built by craftLayer.
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+func craftLayer(t *testing.T, entries ...tarEntry) []byte {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0644, Size: int64(len(e.content))}
+		if e.typeflag == tar.TypeDir {
+			header.Mode = 0755
+		}
+		require.NoError(t, tw.WriteHeader(header))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func TestUnpackLayer_SymlinkChain(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "skill")
+	require.NoError(t, os.Mkdir(dest, 0755))
+
+	// c resolves to ../victim through a/b, although its target looks local
+	layer := craftLayer(t,
+		tarEntry{name: "a", typeflag: tar.TypeDir},
+		tarEntry{name: "a/b", typeflag: tar.TypeSymlink, linkname: ".."},
+		tarEntry{name: "c", typeflag: tar.TypeSymlink, linkname: "a/b/../victim"},
+		tarEntry{name: "c", typeflag: tar.TypeReg, content: "pwned"},
+	)
+	err := unpackLayer(bytes.NewReader(layer), dest)
+	assert.ErrorContains(t, err, "outside the skill directory")
+	_, err = os.Lstat(filepath.Join(parent, "victim"))
+	assert.True(t, os.IsNotExist(err), "victim was written outside the skill directory")
+
+	// Files are never written through a link, even one that stays inside
+	dest = t.TempDir()
+	layer = craftLayer(t,
+		tarEntry{name: "SKILL.md", typeflag: tar.TypeReg, content: "original"},
+		tarEntry{name: "link", typeflag: tar.TypeSymlink, linkname: "SKILL.md"},
+		tarEntry{name: "link", typeflag: tar.TypeReg, content: "overwritten"},
+	)
+	err = unpackLayer(bytes.NewReader(layer), dest)
+	assert.ErrorContains(t, err, "duplicate entry")
+	data, err := os.ReadFile(filepath.Join(dest, "SKILL.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(data))
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/andrewhowdencom/skr/pkg/skill"
+)
+
+// Adapter renders an installed skill set into an agent's native layout, for agents
+// that do not read SKILL.md directories directly.
+type Adapter interface {
+	Render(set SkillSet) error
+}
+
+// SkillSet is the set of skills installed into an agent's skill directory.
+type SkillSet struct {
+	Dir    string
+	Skills []*skill.Skill
+}
+
+// LoadSkillSet reads every skill installed in dir. Entries that are not skills are ignored.
+func LoadSkillSet(dir string) (SkillSet, error) {
+	set := SkillSet{Dir: dir}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return set, nil
+	}
+	if err != nil {
+		return set, fmt.Errorf("failed to read skill directory %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		// Skills may be symlinked into place, so follow links when checking for directories
+		skillPath := filepath.Join(dir, entry.Name())
+		if info, err := os.Stat(skillPath); err != nil || !info.IsDir() {
+			continue
+		}
+
+		s, err := skill.LoadUnverified(skillPath)
+		if err != nil {
+			continue
+		}
+		set.Skills = append(set.Skills, s)
+	}
+
+	sort.Slice(set.Skills, func(i, j int) bool { return set.Skills[i].Name < set.Skills[j].Name })
+	return set, nil
+}
+
+// DirectoryAdapter is used for agents that read SKILL.md directories natively. It renders nothing.
+type DirectoryAdapter struct{}
+
+// Render implements Adapter.
+func (DirectoryAdapter) Render(set SkillSet) error {
+	return nil
+}
+
+// TemplateAdapter renders the skill set through a Go text/template into a single file.
+//
+// The template receives the agent's skill directory as {{.Dir}} and the skills as
+// {{.Skills}}, each with Name, Description, Path (the skill directory relative to Dir)
+// and File (its SKILL.md relative to Dir). A json function is available for
+// generating JSON manifests.
+type TemplateAdapter struct {
+	// Output is the file to write, relative to the agent's skill directory.
+	Output   string `yaml:"output"`
+	Template string `yaml:"template"`
+}
+
+// templateSkill is the per-skill data exposed to adapter templates.
+type templateSkill struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Path         string   `json:"path"`
+	File         string   `json:"file"`
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// Render implements Adapter.
+func (a TemplateAdapter) Render(set SkillSet) error {
+	if a.Output == "" {
+		return fmt.Errorf("template adapter requires an output path")
+	}
+
+	tmpl, err := template.New(a.Output).Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			return string(data), err
+		},
+	}).Parse(a.Template)
+	if err != nil {
+		return fmt.Errorf("invalid adapter template: %w", err)
+	}
+
+	data := struct {
+		Dir    string
+		Skills []templateSkill
+	}{Dir: set.Dir, Skills: []templateSkill{}}
+
+	for _, s := range set.Skills {
+		relPath, err := filepath.Rel(set.Dir, s.Path)
+		if err != nil {
+			relPath = s.Path
+		}
+		data.Skills = append(data.Skills, templateSkill{
+			Name:         s.Name,
+			Description:  s.Description,
+			Path:         filepath.ToSlash(relPath),
+			File:         filepath.ToSlash(filepath.Join(relPath, skill.SkillFileName)),
+			Dependencies: s.Dependencies,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render adapter template: %w", err)
+	}
+
+	outPath := a.Output
+	if !filepath.IsAbs(outPath) {
+		outPath = filepath.Join(set.Dir, outPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", outPath, err)
+	}
+	if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+
+	return nil
+}
+
+const indexTemplate = `# Skills
+
+The following skills are installed. Read a skill's instructions before using it.
+{{range .Skills}}
+- **{{.Name}}** ({{.File}}): {{.Description}}
+{{- end}}
+`
+
+const manifestTemplate = `{{json .Skills}}
+`
+
+// BuiltinAdapters are the adapters skr ships with.
+var BuiltinAdapters = map[string]Adapter{
+	"directory": DirectoryAdapter{},
+	"index":     TemplateAdapter{Output: "INDEX.md", Template: indexTemplate},
+	"json":      TemplateAdapter{Output: "skills.json", Template: manifestTemplate},
+}
+
+// Adapter looks up an adapter by name, preferring adapters defined in the config over the
+// built-in ones. An empty name selects the directory adapter.
+func (c *Config) Adapter(name string) (Adapter, error) {
+	if name == "" {
+		return DirectoryAdapter{}, nil
+	}
+	if a, ok := c.Adapters[name]; ok {
+		return a, nil
+	}
+	if a, ok := BuiltinAdapters[name]; ok {
+		return a, nil
+	}
+	return nil, fmt.Errorf("unknown adapter %q", name)
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"text/template"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,26 +15,256 @@ import (
 const (
 	ConfigFileName = "config.yaml" // XDG style inside skr dir
 	AltConfigName  = ".skr.yaml"   // Legacy/Local
+
+	// CurrentVersion is the newest config file format this version of skr understands.
+	CurrentVersion = 1
+)
+
+// InstallMode controls how skills are placed into the skill directories of additional agents.
+type InstallMode string
+
+const (
+	// InstallModeCopy writes a full copy of each skill into every agent directory.
+	InstallModeCopy InstallMode = "copy"
+	// InstallModeSymlink links every agent directory to a single shared copy.
+	InstallModeSymlink InstallMode = "symlink"
 )
 
-var KnownAgents = map[string]func(home string) string{
-	"standard":    func(home string) string { return filepath.Join(home, ".config", "agent", "skills") },
-	"antigravity": func(home string) string { return filepath.Join(home, ".antigravity", "skills") },
-	"roocode":     func(home string) string { return filepath.Join(home, ".roocode", "skills") },
+// AgentDefinition describes where an agent reads its skills from.
+//
+// Paths are templates expanded with {{.Home}} (the user's home directory) and
+// {{.Project}} (the project root). Relative project paths are resolved against the
+// project root.
+//
+// Adapter names the Adapter that translates the installed skills into the agent's
+// native format; if empty, the agent is assumed to read SKILL.md directories directly.
+type AgentDefinition struct {
+	Project string `yaml:"project,omitempty"`
+	Global  string `yaml:"global,omitempty"`
+	Adapter string `yaml:"adapter,omitempty"`
+}
+
+// KnownAgents are the agents skr knows about without any configuration.
+var KnownAgents = map[string]AgentDefinition{
+	"standard":    {Project: ".agent/skills", Global: "{{.Home}}/.config/agent/skills", Adapter: "directory"},
+	"antigravity": {Project: ".agent/skills", Global: "{{.Home}}/.antigravity/skills", Adapter: "directory"},
+	"roocode":     {Project: ".roocode/skills", Global: "{{.Home}}/.roocode/skills", Adapter: "index"},
+}
+
+// AgentTarget is a configured agent resolved to a concrete skill directory.
+type AgentTarget struct {
+	Name    string
+	Dir     string
+	Adapter string
 }
 
 type Config struct {
-	Agents []string `yaml:"agents"`
-	Skills []string `yaml:"skills"`
+	Version          int                        `yaml:"version,omitempty"`
+	Agents           []string                   `yaml:"agents"`
+	AgentDefinitions map[string]AgentDefinition `yaml:"agent_definitions,omitempty"`
+	Adapters         map[string]TemplateAdapter `yaml:"adapters,omitempty"`
+	InstallMode      InstallMode                `yaml:"install_mode,omitempty"`
+	Registry         string                     `yaml:"registry,omitempty"`
+	Registries       map[string]RegistryConfig  `yaml:"registries,omitempty"`
+	Lint             LintConfig                 `yaml:"lint,omitempty"`
+	Skills           []SkillEntry               `yaml:"skills"`
+}
+
+// LintConfig configures skr validate.
+type LintConfig struct {
+	// Disable lists the IDs of lint rules that are not run.
+	Disable []string `yaml:"disable,omitempty"`
+}
+
+// pathVars are the values available to agent path templates.
+type pathVars struct {
+	Home    string
+	Project string
+}
+
+// ProjectDir returns the directory the agent reads project-scoped skills from.
+func (d AgentDefinition) ProjectDir(projectRoot, home string) (string, error) {
+	if d.Project == "" {
+		return "", nil
+	}
+	path, err := expandPath(d.Project, pathVars{Home: home, Project: projectRoot})
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// GlobalDir returns the directory the agent reads user-scoped skills from.
+func (d AgentDefinition) GlobalDir(home string) (string, error) {
+	if d.Global == "" {
+		return "", nil
+	}
+	path, err := expandPath(d.Global, pathVars{Home: home})
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(home, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+func expandPath(tmpl string, vars pathVars) (string, error) {
+	t, err := template.New("path").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid path template %q: %w", tmpl, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("failed to expand path template %q: %w", tmpl, err)
+	}
+	return buf.String(), nil
+}
+
+// Agent looks up an agent by name, preferring definitions from the config over the built-in ones.
+func (c *Config) Agent(name string) (AgentDefinition, bool) {
+	if def, ok := c.AgentDefinitions[name]; ok {
+		return def, true
+	}
+	def, ok := KnownAgents[name]
+	return def, ok
+}
+
+// AgentTargets resolves every configured agent to its skill directory, either at project
+// scope (rooted at projectRoot) or, if global is set, at user scope. Unknown agents and
+// agents without a directory for the requested scope are skipped.
+func (c *Config) AgentTargets(projectRoot, home string, global bool) ([]AgentTarget, error) {
+	var targets []AgentTarget
+
+	for _, name := range c.Agents {
+		def, ok := c.Agent(name)
+		if !ok {
+			slog.Warn("unknown agent in config, skipping", "agent", name)
+			continue
+		}
+
+		var dir string
+		var err error
+		if global {
+			dir, err = def.GlobalDir(home)
+		} else {
+			dir, err = def.ProjectDir(projectRoot, home)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("agent %s: %w", name, err)
+		}
+		if dir == "" {
+			continue
+		}
+
+		targets = append(targets, AgentTarget{Name: name, Dir: dir, Adapter: def.Adapter})
+	}
+
+	return targets, nil
+}
+
+// AgentDirs returns the unique skill directories of every configured agent. See AgentTargets.
+func (c *Config) AgentDirs(projectRoot, home string, global bool) ([]string, error) {
+	targets, err := c.AgentTargets(projectRoot, home, global)
+	if err != nil {
+		return nil, err
+	}
+	return TargetDirs(targets), nil
+}
+
+// TargetDirs returns the unique directories of targets, in order.
+func TargetDirs(targets []AgentTarget) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		if seen[target.Dir] {
+			continue
+		}
+		seen[target.Dir] = true
+		dirs = append(dirs, target.Dir)
+	}
+	return dirs
 }
 
+// Merge applies other on top of c. See MergeLayers for the override semantics.
 func (c *Config) Merge(other *Config) {
+	c.merge(other, "", nil)
+}
+
+func (c *Config) merge(other *Config, source string, origins Origins) {
 	if other == nil {
 		return
 	}
 
-	// Merge skills (append unique?)
-	c.Skills = append(c.Skills, other.Skills...)
+	if other.Version != 0 {
+		c.Version = other.Version
+		origins.set("version", source)
+	}
+
+	// Merge skills, one entry per repository (other wins)
+	for _, entry := range other.Skills {
+		repo := Repository(entry.Ref)
+		replaced := false
+		for i, existing := range c.Skills {
+			if Repository(existing.Ref) == repo {
+				c.Skills[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.Skills = append(c.Skills, entry)
+		}
+		origins.set("skills."+repo, source)
+	}
+
+	if other.InstallMode != "" {
+		c.InstallMode = other.InstallMode
+		origins.set("install_mode", source)
+	}
+
+	if other.Registry != "" {
+		c.Registry = other.Registry
+		origins.set("registry", source)
+	}
+
+	// Merge registries (other wins)
+	for host, reg := range other.Registries {
+		if c.Registries == nil {
+			c.Registries = make(map[string]RegistryConfig)
+		}
+		c.Registries[host] = reg
+		origins.set("registries."+host, source)
+	}
+
+	// Merge disabled lint rules (append unique)
+	for _, rule := range other.Lint.Disable {
+		if !slices.Contains(c.Lint.Disable, rule) {
+			c.Lint.Disable = append(c.Lint.Disable, rule)
+			origins.set("lint.disable."+rule, source)
+		}
+	}
+
+	// Merge agent definitions (other wins)
+	for name, def := range other.AgentDefinitions {
+		if c.AgentDefinitions == nil {
+			c.AgentDefinitions = make(map[string]AgentDefinition)
+		}
+		c.AgentDefinitions[name] = def
+		origins.set("agent_definitions."+name, source)
+	}
+
+	// Merge adapters (other wins)
+	for name, adapter := range other.Adapters {
+		if c.Adapters == nil {
+			c.Adapters = make(map[string]TemplateAdapter)
+		}
+		c.Adapters[name] = adapter
+		origins.set("adapters."+name, source)
+	}
 
 	// Merge Agents (append unique)
 	for _, agent := range other.Agents {
@@ -44,26 +277,20 @@ func (c *Config) Merge(other *Config) {
 		}
 		if !found {
 			c.Agents = append(c.Agents, agent)
+			origins.set("agents."+agent, source)
 		}
 	}
 }
 
-// FindConfigFile traverses upwards from startDir looking for .skr.yaml or config.yaml
+// FindConfigFile traverses upwards from startDir looking for .skr.yaml. A plain config.yaml
+// is only read from the skr directory of the XDG config directories (see LoadLayers), as
+// any project or directory may have one that has nothing to do with skr.
 func FindConfigFile(startDir string) (string, error) {
 	dir := startDir
 	for i := 0; i < 100; i++ {
-		// Check for .skr.yaml
-		legacyPath := filepath.Join(dir, AltConfigName)
-		if _, err := os.Stat(legacyPath); err == nil {
-			return legacyPath, nil
-		}
-
-		// Check for config.yaml (only if in strict skr dir? or just in root?)
-		// The design says "XDG style inside skr dir" for global, but for local?
-		// Assuming we just look for these files in the project roots.
-		xdgPath := filepath.Join(dir, ConfigFileName)
-		if _, err := os.Stat(xdgPath); err == nil {
-			return xdgPath, nil
+		path := filepath.Join(dir, AltConfigName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
 		}
 
 		parent := filepath.Dir(dir)
@@ -90,47 +317,20 @@ func Load(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
 	}
+	if cfg.Version > CurrentVersion {
+		return nil, fmt.Errorf("config %s has version %d, but this version of skr only supports up to %d", path, cfg.Version, CurrentVersion)
+	}
 
 	return &cfg, nil
 }
 
-// LoadMerged loads global XDG config and merges it with local config found by traversing up from dir.
+// LoadMerged loads every configuration layer that applies to startDir and merges them.
+// See LoadLayers for the layers that are considered.
 func LoadMerged(startDir string) (*Config, error) {
-	// 1. Load Global (XDG)
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		home, _ := os.UserHomeDir()
-		configDir = filepath.Join(home, ".config")
-	}
-
-	globalConfigPath := filepath.Join(configDir, "skr", ConfigFileName)
-	globalCfg, err := Load(globalConfigPath)
-	if err != nil {
-		// Warn?
-		slog.Debug("failed to load global config", "path", globalConfigPath, "error", err)
-	}
-
-	// 2. Load Local
-	// Find config file traversing up
-	if startDir == "" {
-		startDir, _ = os.Getwd()
-	}
-
-	localConfigPath, err := FindConfigFile(startDir)
-	if err == nil {
-		localCfg, err := Load(localConfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load local config: %w", err)
-		}
-		globalCfg.Merge(localCfg)
-	} else {
-		slog.Debug("no local config found in hierarchy", "startDir", startDir)
-	}
-
-	return globalCfg, nil
+	cfg, _, err := LoadLayered(LoadOptions{StartDir: startDir})
+	return cfg, err
 }
 
-// Save persists the config to .skr.yaml in dir
 // Save persists the config to .skr.yaml in dir
 func (c *Config) Save(dir string) error {
 	if dir == "" {
@@ -28,7 +28,7 @@ func TestLoadMerged(t *testing.T) {
 
 	// 1. Create Global Config (XDG)
 	globalCfg := Config{
-		Skills: []string{"global-skill"},
+		Skills: []SkillEntry{{Ref: "global-skill"}},
 		Agents: []string{"antigravity"},
 	}
 	globalData, err := yaml.Marshal(globalCfg)
@@ -43,7 +43,7 @@ func TestLoadMerged(t *testing.T) {
 
 	// 2. Create Local Config in ROOT (Parent of project)
 	localCfg := Config{
-		Skills: []string{"local-skill"},
+		Skills: []SkillEntry{{Ref: "local-skill"}},
 		Agents: []string{"roocode"},
 	}
 	localData, err := yaml.Marshal(localCfg)
@@ -56,11 +56,320 @@ func TestLoadMerged(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify Skills (Appended)
-	assert.Contains(t, cfg.Skills, "global-skill")
-	assert.Contains(t, cfg.Skills, "local-skill")
+	assert.Contains(t, cfg.SkillRefs(), "global-skill")
+	assert.Contains(t, cfg.SkillRefs(), "local-skill")
 
 	// Verify Agents (Merged)
 	assert.Contains(t, cfg.Agents, "antigravity")
 	assert.Contains(t, cfg.Agents, "roocode")
 	assert.Equal(t, 2, len(cfg.Agents))
 }
+
+func TestLoadLayered(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(t.TempDir(), ".config"))
+	t.Setenv("XDG_CONFIG_DIRS", t.TempDir())
+	t.Setenv(EnvInstallMode, "")
+	t.Setenv(EnvSkills, "")
+	t.Setenv(EnvAgents, "standard")
+
+	rootDir := t.TempDir()
+	projectDir := filepath.Join(rootDir, "project")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	rootPath := filepath.Join(rootDir, ".skr.yaml")
+	require.NoError(t, os.WriteFile(rootPath, []byte("agents: [roocode]\ninstall_mode: copy\nskills:\n  - ghcr.io/example/a:v1\n  - ghcr.io/example/b:v1\n"), 0644))
+
+	projectPath := filepath.Join(projectDir, ".skr.yaml")
+	require.NoError(t, os.WriteFile(projectPath, []byte("install_mode: symlink\nskills:\n  - ghcr.io/example/a:v2\n"), 0644))
+
+	explicitPath := filepath.Join(t.TempDir(), "override.yaml")
+	require.NoError(t, os.WriteFile(explicitPath, []byte("skills:\n  - ghcr.io/example/b@sha256:0000000000000000000000000000000000000000000000000000000000000000\n"), 0644))
+
+	cfg, origins, err := LoadLayered(LoadOptions{StartDir: projectDir, ExplicitPath: explicitPath})
+	require.NoError(t, err)
+
+	// The same repository is only listed once, with the nearest layer winning
+	assert.Equal(t, []string{
+		"ghcr.io/example/a:v2",
+		"ghcr.io/example/b@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+	}, cfg.SkillRefs())
+	assert.Equal(t, InstallModeSymlink, cfg.InstallMode)
+	assert.Equal(t, []string{"roocode", "standard"}, cfg.Agents)
+
+	assert.Equal(t, projectPath, origins["skills.ghcr.io/example/a"])
+	assert.Equal(t, explicitPath, origins["skills.ghcr.io/example/b"])
+	assert.Equal(t, projectPath, origins["install_mode"])
+	assert.Equal(t, rootPath, origins["agents.roocode"])
+	assert.Equal(t, SourceEnv, origins["agents.standard"])
+
+	// A missing explicit config is an error
+	_, _, err = LoadLayered(LoadOptions{StartDir: projectDir, ExplicitPath: filepath.Join(rootDir, "missing.yaml")})
+	assert.Error(t, err)
+}
+
+func TestFindConfigFilesIgnoresConfigYAML(t *testing.T) {
+	rootDir := t.TempDir()
+	projectDir := filepath.Join(rootDir, "project")
+	require.NoError(t, os.MkdirAll(projectDir, 0755))
+
+	// An unrelated config.yaml above the project is not skr's
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, ConfigFileName), []byte("version: not-a-number\n"), 0644))
+	projectPath := filepath.Join(projectDir, AltConfigName)
+	require.NoError(t, os.WriteFile(projectPath, []byte("version: 1\n"), 0644))
+
+	assert.Equal(t, []string{projectPath}, FindConfigFiles(projectDir))
+
+	path, err := FindConfigFile(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, projectPath, path)
+}
+
+func TestLoadLayeredRegistryPaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(t.TempDir(), ".config"))
+	t.Setenv("XDG_CONFIG_DIRS", t.TempDir())
+
+	projectDir := t.TempDir()
+	subDir := filepath.Join(projectDir, "docs")
+	require.NoError(t, os.MkdirAll(subDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, AltConfigName), []byte(`version: 1
+registries:
+  registry.internal:
+    ca_file: certs/ca.pem
+    cert_file: /etc/ssl/client.pem
+    key_file: /etc/ssl/client-key.pem
+`), 0644))
+
+	// Paths are relative to the file that set them, not the working directory
+	cfg, _, err := LoadLayered(LoadOptions{StartDir: subDir})
+	require.NoError(t, err)
+	reg := cfg.RegistryFor("registry.internal")
+	assert.Equal(t, filepath.Join(projectDir, "certs", "ca.pem"), reg.CAFile)
+	assert.Equal(t, "/etc/ssl/client.pem", reg.CertFile)
+}
+
+func TestAgentDirs(t *testing.T) {
+	home := "/home/user"
+	projectRoot := "/src/project"
+
+	cfg := Config{
+		Agents: []string{"standard", "antigravity", "roocode", "custom", "unknown"},
+		AgentDefinitions: map[string]AgentDefinition{
+			"custom": {
+				Project: "{{.Project}}/tools/custom/skills",
+				Global:  "{{.Home}}/.custom/skills",
+			},
+		},
+	}
+
+	// Project scope: standard and antigravity share .agent/skills
+	dirs, err := cfg.AgentDirs(projectRoot, home, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/src/project/.agent/skills",
+		"/src/project/.roocode/skills",
+		"/src/project/tools/custom/skills",
+	}, dirs)
+
+	// Global scope
+	dirs, err = cfg.AgentDirs(projectRoot, home, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/home/user/.config/agent/skills",
+		"/home/user/.antigravity/skills",
+		"/home/user/.roocode/skills",
+		"/home/user/.custom/skills",
+	}, dirs)
+
+	// Custom definitions override built-in ones
+	cfg.AgentDefinitions["roocode"] = AgentDefinition{Project: ".roo/skills"}
+	dirs, err = cfg.AgentDirs(projectRoot, home, false)
+	require.NoError(t, err)
+	assert.Contains(t, dirs, "/src/project/.roo/skills")
+}
+
+func TestTemplateAdapter(t *testing.T) {
+	dir := t.TempDir()
+
+	createSkill := func(name, description string) {
+		skillDir := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(skillDir, 0755))
+		content := "---\nname: " + name + "\ndescription: " + description + "\n---\n"
+		require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644))
+	}
+	createSkill("beta", "Second skill")
+	createSkill("alpha", "First skill")
+
+	set, err := LoadSkillSet(dir)
+	require.NoError(t, err)
+	require.Len(t, set.Skills, 2)
+
+	// Built-in index adapter
+	cfg := Config{}
+	adapter, err := cfg.Adapter("index")
+	require.NoError(t, err)
+	require.NoError(t, adapter.Render(set))
+
+	index, err := os.ReadFile(filepath.Join(dir, "INDEX.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "- **alpha** (alpha/SKILL.md): First skill\n- **beta** (beta/SKILL.md): Second skill")
+
+	// Custom adapter from config
+	cfg.Adapters = map[string]TemplateAdapter{
+		"rules": {Output: "../rules/skills.txt", Template: "{{range .Skills}}{{.Name}}={{.Path}}\n{{end}}"},
+	}
+	adapter, err = cfg.Adapter("rules")
+	require.NoError(t, err)
+	require.NoError(t, adapter.Render(set))
+
+	rules, err := os.ReadFile(filepath.Join(filepath.Dir(dir), "rules", "skills.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "alpha=alpha\nbeta=beta\n", string(rules))
+
+	_, err = cfg.Adapter("missing")
+	assert.Error(t, err)
+}
+
+func TestSkillEntryYAML(t *testing.T) {
+	data := []byte(`version: 1
+skills:
+  - ghcr.io/example/plain:v1
+  - ref: ghcr.io/example/pinned:v1
+    pin: sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+    agents: [roocode]
+    optional: true
+`)
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	require.Len(t, cfg.Skills, 2)
+
+	assert.Equal(t, SkillEntry{Ref: "ghcr.io/example/plain:v1"}, cfg.Skills[0])
+	assert.Equal(t, "ghcr.io/example/plain:v1", cfg.Skills[0].InstallRef())
+
+	pinned := cfg.Skills[1]
+	assert.True(t, pinned.Optional)
+	assert.True(t, pinned.ForAgent("roocode"))
+	assert.False(t, pinned.ForAgent("standard"))
+	assert.Equal(t, "ghcr.io/example/pinned@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", pinned.InstallRef())
+
+	// Round trip keeps plain entries as strings
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "- ghcr.io/example/plain:v1\n")
+	assert.Contains(t, string(out), "ref: ghcr.io/example/pinned:v1")
+}
+
+func TestValidate(t *testing.T) {
+	data := []byte(`version: 1
+agents: [roocode]
+colour: red
+skills:
+  - ghcr.io/example/plain:v1
+  - "Not A Ref"
+  - ref: ghcr.io/example/pinned:v1
+    pin: not-a-digest
+`)
+
+	problems := Validate(data)
+	require.Len(t, problems, 3)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Contains(t, problems[0].Message, `unknown key "colour"`)
+	assert.Equal(t, 6, problems[1].Line)
+	assert.Contains(t, problems[1].Message, "invalid reference")
+	assert.Equal(t, 8, problems[2].Line)
+	assert.Contains(t, problems[2].Message, "invalid pin")
+}
+
+func TestValidateRegistries(t *testing.T) {
+	data := []byte(`version: 1
+registries:
+  localhost:5000:
+    plain_http: yes please
+  registry.internal:
+    ca_file: /etc/ssl/internal.pem
+    cert_file: /etc/ssl/client.pem
+`)
+
+	problems := Validate(data)
+	require.Len(t, problems, 2)
+	assert.Equal(t, 4, problems[0].Line)
+	assert.Contains(t, problems[0].Message, "expected true or false")
+	assert.Equal(t, 7, problems[1].Line)
+	assert.Contains(t, problems[1].Message, "cert_file requires a key_file")
+}
+
+func TestDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".skr.yaml")
+	original := `# Project skills
+version: 1
+
+# Agents to install into
+agents: [roocode]
+
+skills:
+  # The git skill
+  - ghcr.io/example/skills.git:v1 # latest stable
+  # The go skill
+  - ghcr.io/example/skills.go:v1
+`
+	require.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+	doc, err := LoadDocument(path)
+	require.NoError(t, err)
+
+	require.NoError(t, doc.Add("agents", "standard", "roocode"))
+	require.NoError(t, doc.Set("install_mode", "symlink"))
+	require.NoError(t, doc.Set("registries.ghcr.io.namespace", "example"))
+	require.NoError(t, doc.Set("skills.ghcr.io/example/skills.go:v1.optional", "true"))
+	require.NoError(t, doc.Add("skills", "ghcr.io/example/skills.md:v1"))
+	require.NoError(t, doc.Remove("skills", "ghcr.io/example/skills.git:v1"))
+
+	// Invalid keys and values are rejected
+	assert.Error(t, doc.Set("colour", "blue"))
+	assert.Error(t, doc.Set("agents", "roocode"))
+	assert.ErrorIs(t, doc.Remove("skills", "ghcr.io/example/missing:v1"), ErrNotSet)
+
+	require.NoError(t, doc.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+
+	// Comments survive the round trip
+	assert.Contains(t, content, "# Project skills")
+	assert.Contains(t, content, "# Agents to install into")
+	assert.Contains(t, content, "# The go skill")
+	assert.NotContains(t, content, "# latest stable")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"roocode", "standard"}, cfg.Agents)
+	assert.Equal(t, InstallModeSymlink, cfg.InstallMode)
+	assert.Equal(t, "example", cfg.RegistryFor("ghcr.io").Namespace)
+	assert.Equal(t, []string{"ghcr.io/example/skills.go:v1", "ghcr.io/example/skills.md:v1"}, cfg.SkillRefs())
+	assert.True(t, cfg.Skills[0].Optional)
+
+	node, err := doc.Get("registries.ghcr.io.namespace")
+	require.NoError(t, err)
+	assert.Equal(t, "example", node.Value)
+
+	// Invalid values are not written
+	require.NoError(t, doc.Set("install_mode", "hardlink"))
+	assert.Error(t, doc.Save())
+}
+
+func TestDocumentSaveUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".skr.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("version: 1\nfuture_key: true\nskills: []\n"), 0644))
+
+	// Keys from other (e.g. newer) versions of skr don't keep the file from being edited
+	doc, err := LoadDocument(path)
+	require.NoError(t, err)
+	require.NoError(t, doc.Add("skills", "ghcr.io/example/skills.go:v1"))
+	require.NoError(t, doc.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "future_key: true")
+	assert.Contains(t, string(data), "ghcr.io/example/skills.go:v1")
+}
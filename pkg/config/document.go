@@ -0,0 +1,398 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotSet is returned when a key is not set in a Document.
+var ErrNotSet = errors.New("not set")
+
+// Document is a config file opened for editing. Edits are made to the YAML node tree, so
+// that comments and the order of keys survive being written back.
+//
+// Keys are dotted paths through the config, e.g. "install_mode", "agents",
+// "registries.ghcr.io.namespace" or "skills.<ref or name>.pin". Map keys and skill
+// references may themselves contain dots.
+type Document struct {
+	path string
+	root *yaml.Node
+}
+
+// KeyValue is a single value of a Document, as returned by List.
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// location is a node in the document, along with its parent and position.
+type location struct {
+	parent *yaml.Node
+	// index is the index of the key (for a mapping parent) or the item (for a sequence parent).
+	index int
+	node  *yaml.Node
+	field *field
+}
+
+// step is a single component of a parsed key.
+type step struct {
+	name  string
+	field *field
+}
+
+// LoadDocument opens the config file at path for editing. A missing file is treated as empty.
+func LoadDocument(path string) (*Document, error) {
+	doc := &Document{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config %s is not a mapping", path)
+	}
+
+	doc.root = &root
+	return doc, nil
+}
+
+// Path returns the path of the config file.
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Get returns the node stored at key, or ErrNotSet.
+func (d *Document) Get(key string) (*yaml.Node, error) {
+	loc, err := d.find(key, false)
+	if err != nil {
+		return nil, err
+	}
+	return loc.node, nil
+}
+
+// Set stores value at key, creating any intermediate mappings. Only single values can be
+// set; lists are changed with Add and Remove.
+func (d *Document) Set(key, value string) error {
+	loc, err := d.find(key, true)
+	if err != nil {
+		return err
+	}
+	if loc.field.kind != yaml.ScalarNode {
+		return fmt.Errorf("%s is not a single value (use add or remove)", key)
+	}
+
+	// Let the encoder choose the style, but keep any comments
+	loc.node.Kind = yaml.ScalarNode
+	loc.node.Tag = ""
+	loc.node.Style = 0
+	loc.node.Value = value
+	loc.node.Content = nil
+	return nil
+}
+
+// Add appends values to the list at key, skipping values that are already present.
+func (d *Document) Add(key string, values ...string) error {
+	loc, err := d.find(key, true)
+	if err != nil {
+		return err
+	}
+	if loc.field.kind != yaml.SequenceNode {
+		return fmt.Errorf("%s is not a list (use set)", key)
+	}
+	if loc.node.Kind != yaml.SequenceNode {
+		// Replace an empty value
+		*loc.node = yaml.Node{Kind: yaml.SequenceNode, HeadComment: loc.node.HeadComment, LineComment: loc.node.LineComment}
+	}
+
+	for _, value := range values {
+		if indexOf(loc.node, loc.field, value) != -1 {
+			continue
+		}
+		loc.node.Content = append(loc.node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+	}
+	return nil
+}
+
+// Remove deletes values from the list at key. If no values are given, key itself is deleted.
+func (d *Document) Remove(key string, values ...string) error {
+	loc, err := d.find(key, false)
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		switch loc.parent.Kind {
+		case yaml.MappingNode:
+			loc.parent.Content = append(loc.parent.Content[:loc.index], loc.parent.Content[loc.index+2:]...)
+		case yaml.SequenceNode:
+			removeItem(loc.parent, loc.index)
+		}
+		return nil
+	}
+
+	if loc.node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s is not a list", key)
+	}
+	for _, value := range values {
+		i := indexOf(loc.node, loc.field, value)
+		if i == -1 {
+			return fmt.Errorf("%s does not contain %q: %w", key, value, ErrNotSet)
+		}
+		removeItem(loc.node, i)
+	}
+	return nil
+}
+
+// List returns every value in the document. List items are reported under the key of the list.
+func (d *Document) List() []KeyValue {
+	var values []KeyValue
+	flatten(d.root.Content[0], schema, "", &values)
+	return values
+}
+
+// Config decodes the document.
+func (d *Document) Config() (*Config, error) {
+	var cfg Config
+	if err := d.root.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config %s: %w", d.path, err)
+	}
+	return &cfg, nil
+}
+
+// Bytes encodes the document.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d.root); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save validates the document and writes it back to its file. Warnings, such as unknown keys,
+// are logged rather than keeping the document from being written.
+func (d *Document) Save() error {
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	for _, p := range Validate(data) {
+		if !p.Warning {
+			return fmt.Errorf("refusing to write invalid config %s: %s", d.path, p.Message)
+		}
+		slog.Warn("config has a problem", "path", d.path, "line", p.Line, "problem", p.Message)
+	}
+	if err := os.WriteFile(d.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config to %s: %w", d.path, err)
+	}
+	return nil
+}
+
+// find locates key in the document. If create is set, missing mapping keys are added
+// (skill entries are never created implicitly).
+func (d *Document) find(key string, create bool) (location, error) {
+	if key == "" {
+		return location{}, fmt.Errorf("key is empty")
+	}
+	steps, err := parseKey(schema, strings.Split(key, "."))
+	if err != nil {
+		return location{}, fmt.Errorf("invalid key %q: %w", key, err)
+	}
+
+	loc := location{node: d.root.Content[0], field: schema}
+	for _, s := range steps {
+		node := loc.node
+
+		// A scalar may be shorthand for a mapping (e.g. a skill written as a plain reference)
+		if node.Kind == yaml.ScalarNode && loc.field.shorthand != "" {
+			if s.name == loc.field.shorthand {
+				loc = location{parent: loc.parent, index: loc.index, node: node, field: s.field}
+				continue
+			}
+			if !create {
+				return location{}, fmt.Errorf("%s: %w", key, ErrNotSet)
+			}
+			*node = yaml.Node{
+				Kind:        yaml.MappingNode,
+				HeadComment: node.HeadComment,
+				LineComment: node.LineComment,
+				Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Value: loc.field.shorthand},
+					{Kind: yaml.ScalarNode, Value: node.Value},
+				},
+			}
+		}
+
+		switch {
+		case node.Kind == yaml.SequenceNode && loc.field.match != nil:
+			i := indexOf(node, loc.field, s.name)
+			if i == -1 {
+				return location{}, fmt.Errorf("%s: no entry %q: %w", key, s.name, ErrNotSet)
+			}
+			loc = location{parent: node, index: i, node: node.Content[i], field: s.field}
+		case node.Kind == yaml.MappingNode || (create && isNull(node)):
+			if node.Kind != yaml.MappingNode {
+				*node = yaml.Node{Kind: yaml.MappingNode, HeadComment: node.HeadComment, LineComment: node.LineComment}
+			}
+			i := mapIndex(node, s.name)
+			if i == -1 {
+				if !create {
+					return location{}, fmt.Errorf("%s: %w", key, ErrNotSet)
+				}
+				i = len(node.Content)
+				node.Content = append(node.Content,
+					&yaml.Node{Kind: yaml.ScalarNode, Value: s.name},
+					&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"},
+				)
+			}
+			loc = location{parent: node, index: i, node: node.Content[i+1], field: s.field}
+		default:
+			return location{}, fmt.Errorf("%s: %w", key, ErrNotSet)
+		}
+	}
+
+	return loc, nil
+}
+
+// parseKey splits a dotted key into steps according to the schema. Keys of maps with
+// arbitrary keys and identifiers of sequence items may contain dots, so the shortest
+// prefix that leaves a valid remainder is used.
+func parseKey(f *field, segments []string) ([]step, error) {
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	if f.fields != nil {
+		child, ok := f.fields[segments[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", segments[0])
+		}
+		rest, err := parseKey(child, segments[1:])
+		if err != nil {
+			return nil, err
+		}
+		return append([]step{{name: segments[0], field: child}}, rest...), nil
+	}
+
+	if f.values != nil && (f.kind == yaml.MappingNode || f.match != nil) {
+		var lastErr error
+		for k := 1; k <= len(segments); k++ {
+			rest, err := parseKey(f.values, segments[k:])
+			if err == nil {
+				return append([]step{{name: strings.Join(segments[:k], "."), field: f.values}}, rest...), nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+
+	return nil, fmt.Errorf("%q is not a key", strings.Join(segments, "."))
+}
+
+func flatten(node *yaml.Node, f *field, prefix string, values *[]KeyValue) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child := f.values
+			if f.fields != nil {
+				child = f.fields[key.Value]
+			}
+			if child == nil {
+				continue
+			}
+			flatten(value, child, joinPath(prefix, key.Value), values)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode || f.values == nil {
+				flatten(item, scalar, prefix, values)
+				continue
+			}
+
+			// Report the identifier under the list, and the rest under the item
+			id := ""
+			if f.values.shorthand != "" {
+				if i := mapIndex(item, f.values.shorthand); i != -1 {
+					id = item.Content[i+1].Value
+				}
+			}
+			*values = append(*values, KeyValue{Key: prefix, Value: id})
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				key, value := item.Content[i], item.Content[i+1]
+				child := f.values.fields[key.Value]
+				if key.Value == f.values.shorthand || child == nil {
+					continue
+				}
+				flatten(value, child, joinPath(prefix, id+"."+key.Value), values)
+			}
+		}
+	case yaml.ScalarNode:
+		if !isNull(node) {
+			*values = append(*values, KeyValue{Key: prefix, Value: node.Value})
+		}
+	}
+}
+
+// removeItem removes the i-th item of seq. Comments above the first item usually describe
+// the whole list, so they are kept on the item that takes its place.
+func removeItem(seq *yaml.Node, i int) {
+	if i == 0 && len(seq.Content) > 1 && seq.Content[0].HeadComment != "" {
+		next := seq.Content[1]
+		if next.HeadComment != "" {
+			next.HeadComment = seq.Content[0].HeadComment + "\n\n" + next.HeadComment
+		} else {
+			next.HeadComment = seq.Content[0].HeadComment
+		}
+	}
+	seq.Content = append(seq.Content[:i], seq.Content[i+1:]...)
+}
+
+// indexOf returns the index of the item of seq identified by value, or -1.
+func indexOf(seq *yaml.Node, f *field, value string) int {
+	for i, item := range seq.Content {
+		if f.match != nil {
+			if f.match(item, value) {
+				return i
+			}
+		} else if item.Kind == yaml.ScalarNode && item.Value == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// mapIndex returns the index of key in mapping, or -1.
+func mapIndex(mapping *yaml.Node, key string) int {
+	if mapping.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && (node.Tag == "!!null" || (node.Tag == "" && node.Value == ""))
+}
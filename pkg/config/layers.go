@@ -0,0 +1,291 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SourceFlag is the origin of values set by the --config flag, if no path is known.
+	SourceFlag = "--config"
+	// SourceEnv is the origin of values set through environment variables.
+	SourceEnv = "environment"
+)
+
+// Environment variables that override the configuration files.
+const (
+	EnvAgents      = "SKR_AGENTS"       // Comma separated agent names
+	EnvSkills      = "SKR_SKILLS"       // Comma separated skill references
+	EnvInstallMode = "SKR_INSTALL_MODE" // copy or symlink
+)
+
+// Layer is a single source of configuration.
+type Layer struct {
+	// Source is the path of the file the layer was read from, or SourceEnv.
+	Source string
+	Config *Config
+}
+
+// LoadOptions controls which layers are loaded.
+type LoadOptions struct {
+	// StartDir is the directory from which .skr.yaml files are searched for. Defaults to the
+	// working directory.
+	StartDir string
+	// ExplicitPath is a configuration file that takes precedence over all other files
+	// (e.g. from the --config flag).
+	ExplicitPath string
+	// Global skips the project layers, for operations on the user's global skill set.
+	Global bool
+}
+
+// Origins maps the keys of the effective configuration to the source that contributed
+// them. Keys are top-level field names (e.g. "install_mode"), or the field name and the
+// item for collections (e.g. "agents.roocode", or "skills.ghcr.io/example/skill", keyed
+// by repository).
+type Origins map[string]string
+
+func (o Origins) set(key, source string) {
+	if o != nil {
+		o[key] = source
+	}
+}
+
+// LoadLayers loads every configuration layer, from lowest to highest precedence:
+//
+//  1. System: skr/config.yaml in each of $XDG_CONFIG_DIRS (default /etc/xdg)
+//  2. User: skr/config.yaml in the user config directory (e.g. ~/.config)
+//  3. Project: every .skr.yaml from the filesystem root down to StartDir,
+//     unless Global is set
+//  4. The file given by ExplicitPath
+//  5. Environment variables (SKR_AGENTS, SKR_SKILLS, SKR_INSTALL_MODE)
+//
+// Missing files are skipped.
+func LoadLayers(opts LoadOptions) ([]Layer, error) {
+	var layers []Layer
+	seen := make(map[string]bool)
+
+	add := func(path string, required bool) error {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+
+		if _, err := os.Stat(path); err != nil {
+			if required {
+				return fmt.Errorf("config file %s not found: %w", path, err)
+			}
+			slog.Debug("config file not found", "path", path)
+			return nil
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			return err
+		}
+		cfg.resolvePaths(filepath.Dir(path))
+		layers = append(layers, Layer{Source: path, Config: cfg})
+		return nil
+	}
+
+	// 1. System (XDG_CONFIG_DIRS is in order of preference, so apply it in reverse)
+	systemDirs := filepath.SplitList(os.Getenv("XDG_CONFIG_DIRS"))
+	if len(systemDirs) == 0 {
+		systemDirs = []string{"/etc/xdg"}
+	}
+	for i := len(systemDirs) - 1; i >= 0; i-- {
+		if systemDirs[i] == "" {
+			continue
+		}
+		if err := add(filepath.Join(systemDirs[i], "skr", ConfigFileName), false); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. User (XDG)
+	if userPath := UserConfigPath(); userPath != "" {
+		if err := add(userPath, false); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Project, outermost first so that nearer files win
+	if !opts.Global {
+		startDir := opts.StartDir
+		if startDir == "" {
+			startDir, _ = os.Getwd()
+		}
+		projectPaths := FindConfigFiles(startDir)
+		if len(projectPaths) == 0 {
+			slog.Debug("no local config found in hierarchy", "startDir", startDir)
+		}
+		for i := len(projectPaths) - 1; i >= 0; i-- {
+			if err := add(projectPaths[i], false); err != nil {
+				return nil, fmt.Errorf("failed to load local config: %w", err)
+			}
+		}
+	}
+
+	// 4. Explicit
+	if opts.ExplicitPath != "" {
+		if err := add(opts.ExplicitPath, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Environment
+	if env := envConfig(); env != nil {
+		layers = append(layers, Layer{Source: SourceEnv, Config: env})
+	}
+
+	return layers, nil
+}
+
+// MergeLayers merges layers in order, later layers overriding earlier ones:
+//
+//   - version, install_mode and registry: the last layer that sets them wins.
+//   - agents and lint.disable: the union of every layer, in the order they first appear.
+//   - agent_definitions, adapters and registries: merged by name, the last definition wins.
+//   - skills: merged by repository, so that the same skill at two different tags or
+//     digests is only installed once. The last entry wins, keeping its original position.
+func MergeLayers(layers []Layer) (*Config, Origins) {
+	cfg := &Config{}
+	origins := make(Origins)
+	for _, layer := range layers {
+		cfg.merge(layer.Config, layer.Source, origins)
+	}
+	return cfg, origins
+}
+
+// LoadLayered loads and merges every configuration layer. See LoadLayers and MergeLayers.
+func LoadLayered(opts LoadOptions) (*Config, Origins, error) {
+	layers, err := LoadLayers(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, origins := MergeLayers(layers)
+	return cfg, origins, nil
+}
+
+// UserConfigPath returns the path of the user's global configuration file.
+func UserConfigPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "skr", ConfigFileName)
+}
+
+// FindConfigFiles returns every project configuration file from startDir upwards, nearest first.
+func FindConfigFiles(startDir string) []string {
+	var paths []string
+	dir := startDir
+	for {
+		path, err := FindConfigFile(dir)
+		if err != nil {
+			break
+		}
+		paths = append(paths, path)
+
+		parent := filepath.Dir(filepath.Dir(path))
+		if parent == filepath.Dir(path) {
+			break
+		}
+		dir = parent
+	}
+	return paths
+}
+
+// envConfig builds a configuration layer from environment variables, or returns nil if none are set.
+func envConfig() *Config {
+	cfg := &Config{}
+	set := false
+
+	if v := os.Getenv(EnvAgents); v != "" {
+		cfg.Agents = splitList(v)
+		set = true
+	}
+	if v := os.Getenv(EnvSkills); v != "" {
+		for _, ref := range splitList(v) {
+			cfg.Skills = append(cfg.Skills, SkillEntry{Ref: ref})
+		}
+		set = true
+	}
+	if v := os.Getenv(EnvInstallMode); v != "" {
+		cfg.InstallMode = InstallMode(v)
+		set = true
+	}
+
+	if !set {
+		return nil
+	}
+	return cfg
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Annotate encodes cfg as a YAML document, with a comment on each value naming the source
+// recorded for it in o.
+func (o Origins) Annotate(cfg *Config) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := doc.Encode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+
+		switch key.Value {
+		case "agents":
+			for _, item := range value.Content {
+				item.LineComment = o[key.Value+"."+item.Value]
+			}
+		case "skills":
+			for _, item := range value.Content {
+				// Entries are either a plain reference or a mapping with a ref key
+				refNode := item
+				for j := 0; j+1 < len(item.Content); j += 2 {
+					if item.Content[j].Value == "ref" {
+						refNode = item.Content[j+1]
+					}
+				}
+				refNode.LineComment = o[key.Value+"."+Repository(refNode.Value)]
+			}
+		case "lint":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				if value.Content[j].Value != "disable" {
+					continue
+				}
+				for _, item := range value.Content[j+1].Content {
+					item.LineComment = o["lint.disable."+item.Value]
+				}
+			}
+		case "agent_definitions", "adapters", "registries":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				value.Content[j].LineComment = o[key.Value+"."+value.Content[j].Value]
+			}
+		default:
+			value.LineComment = o[key.Value]
+		}
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// DefaultRegistry is the registry used when none is configured.
+const DefaultRegistry = "ghcr.io"
+
+// RegistryConfig holds the settings for a single registry host.
+type RegistryConfig struct {
+	// Namespace is the default namespace to publish skills under (e.g. a user or organization).
+	Namespace string `yaml:"namespace,omitempty"`
+	// Index is the URL (or path) of a search index of the registry's skills, as published
+	// by skr http generate. It is used by skr search instead of listing the registry.
+	Index string `yaml:"index,omitempty"`
+	// PlainHTTP connects to the registry over HTTP instead of HTTPS.
+	PlainHTTP bool `yaml:"plain_http,omitempty"`
+	// Insecure skips the verification of the registry's TLS certificate.
+	Insecure bool `yaml:"insecure,omitempty"`
+	// CAFile is a PEM file of certificate authorities to trust for the registry, in
+	// addition to the system's. Relative paths (like those of CertFile and KeyFile) are
+	// relative to the configuration file.
+	CAFile string `yaml:"ca_file,omitempty"`
+	// CertFile and KeyFile are a PEM client certificate and its key, for mutual TLS.
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+}
+
+// DefaultRegistryHost returns the configured default registry, or DefaultRegistry.
+func (c *Config) DefaultRegistryHost() string {
+	if c.Registry != "" {
+		return c.Registry
+	}
+	return DefaultRegistry
+}
+
+// RegistryFor returns the settings for host. Hosts without settings get the zero value.
+func (c *Config) RegistryFor(host string) RegistryConfig {
+	return c.Registries[host]
+}
+
+// RegistryHosts returns the default registry followed by every other configured registry.
+func (c *Config) RegistryHosts() []string {
+	hosts := []string{c.DefaultRegistryHost()}
+	var others []string
+	for host := range c.Registries {
+		if host != hosts[0] {
+			others = append(others, host)
+		}
+	}
+	sort.Strings(others)
+	return append(hosts, others...)
+}
+
+// resolvePaths makes the relative file paths of the registry settings relative to dir, the
+// directory of the configuration file they were read from.
+func (c *Config) resolvePaths(dir string) {
+	for host, reg := range c.Registries {
+		for _, p := range []*string{&reg.CAFile, &reg.CertFile, &reg.KeyFile} {
+			if *p != "" && !filepath.IsAbs(*p) {
+				*p = filepath.Join(dir, *p)
+			}
+		}
+		c.Registries[host] = reg
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	"gopkg.in/yaml.v3"
+	"oras.land/oras-go/v2/registry"
+)
+
+// SkillEntry is a skill listed in the config. It may be written either as a plain
+// reference string or as a mapping with per-skill options:
+//
+//	skills:
+//	  - ghcr.io/example/skills.git:latest
+//	  - ref: ghcr.io/example/skills.go:v1
+//	    pin: sha256:...
+//	    agents: [roocode]
+//	    optional: true
+type SkillEntry struct {
+	// Ref is the OCI reference of the skill.
+	Ref string `yaml:"ref"`
+	// Name is a local name for the skill, which can be used instead of Ref (e.g. with skr rm).
+	Name string `yaml:"name,omitempty"`
+	// Agents restricts which of the configured agents receive the skill.
+	Agents []string `yaml:"agents,omitempty"`
+	// Pin is the digest the skill must resolve to. If set, it is installed by digest.
+	Pin string `yaml:"pin,omitempty"`
+	// Optional skills do not fail a sync if they cannot be installed.
+	Optional bool `yaml:"optional,omitempty"`
+	// Verify is the ID of the key the skill must be signed with.
+	Verify string `yaml:"verify,omitempty"`
+}
+
+// UnmarshalYAML accepts both the plain string and the mapping form.
+func (e *SkillEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*e = SkillEntry{}
+		return node.Decode(&e.Ref)
+	}
+
+	type plain SkillEntry
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	if p.Ref == "" {
+		return fmt.Errorf("line %d: skill entry is missing a ref", node.Line)
+	}
+
+	*e = SkillEntry(p)
+	return nil
+}
+
+// MarshalYAML writes entries without options in the plain string form.
+func (e SkillEntry) MarshalYAML() (interface{}, error) {
+	if e.Name == "" && len(e.Agents) == 0 && e.Pin == "" && !e.Optional && e.Verify == "" {
+		return e.Ref, nil
+	}
+
+	type plain SkillEntry
+	return plain(e), nil
+}
+
+// InstallRef returns the reference to install. Pinned skills are installed by digest.
+func (e SkillEntry) InstallRef() string {
+	if e.Pin == "" {
+		return e.Ref
+	}
+	return Repository(e.Ref) + "@" + e.Pin
+}
+
+// Matches reports whether the entry is identified by ref, either by reference or by name.
+func (e SkillEntry) Matches(ref string) bool {
+	return e.Ref == ref || (e.Name != "" && e.Name == ref)
+}
+
+// ForAgent reports whether the skill should be installed for the named agent.
+func (e SkillEntry) ForAgent(agent string) bool {
+	if len(e.Agents) == 0 {
+		return true
+	}
+	for _, a := range e.Agents {
+		if a == agent {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the entry's reference and pin are well formed.
+func (e SkillEntry) Validate() error {
+	if err := ValidateRef(e.Ref); err != nil {
+		return err
+	}
+	if e.Pin != "" {
+		if _, err := digest.Parse(e.Pin); err != nil {
+			return fmt.Errorf("invalid pin %q: %w", e.Pin, err)
+		}
+	}
+	return nil
+}
+
+// SkillRefs returns the references of all configured skills.
+func (c *Config) SkillRefs() []string {
+	refs := make([]string, 0, len(c.Skills))
+	for _, s := range c.Skills {
+		refs = append(refs, s.Ref)
+	}
+	return refs
+}
+
+// ValidateRef checks that ref is a valid OCI reference. References to the local store
+// without a registry (e.g. "my-skill:v1") are accepted as well.
+func ValidateRef(ref string) error {
+	if ref == "" {
+		return fmt.Errorf("reference is empty")
+	}
+
+	if _, err := registry.ParseReference(ref); err != nil {
+		if strings.Contains(ref, "/") {
+			return fmt.Errorf("invalid reference %q: %w", ref, err)
+		}
+		// Local references have no registry component
+		if _, localErr := registry.ParseReference("localhost/" + ref); localErr != nil {
+			return fmt.Errorf("invalid reference %q: %w", ref, err)
+		}
+	}
+	return nil
+}
+
+// Repository strips the tag or digest from ref.
+func Repository(ref string) string {
+	if idx := strings.Index(ref, "@"); idx != -1 {
+		ref = ref[:idx]
+	}
+	if idx := strings.LastIndex(ref, ":"); idx != -1 && !strings.Contains(ref[idx:], "/") {
+		ref = ref[:idx]
+	}
+	return ref
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/opencontainers/go-digest"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is an issue found while validating a config file.
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+	// Warning marks problems that don't keep the config from loading, such as unknown keys
+	// (e.g. from a newer version of skr).
+	Warning bool
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+// field describes the expected shape of a config value.
+type field struct {
+	// kind is the expected node kind, or 0 if any kind is accepted.
+	kind yaml.Kind
+	// fields are the known keys of a mapping.
+	fields map[string]*field
+	// values describes the items of a sequence, or the values of a mapping with arbitrary keys.
+	values *field
+	// check performs additional validation of the node.
+	check func(node *yaml.Node) []Problem
+	// match reports whether an item of a sequence is identified by id, so that it can be
+	// addressed by key (e.g. skills.<ref>.pin).
+	match func(item *yaml.Node, id string) bool
+	// shorthand is the key that a scalar in place of this mapping stands for (e.g. a skill
+	// written as a plain reference is shorthand for its ref).
+	shorthand string
+}
+
+var scalar = &field{kind: yaml.ScalarNode}
+
+var schema = &field{
+	kind: yaml.MappingNode,
+	fields: map[string]*field{
+		"version": {kind: yaml.ScalarNode, check: checkVersion},
+		"agents":  {kind: yaml.SequenceNode, values: scalar},
+		"agent_definitions": {
+			kind: yaml.MappingNode,
+			values: &field{
+				kind:   yaml.MappingNode,
+				fields: map[string]*field{"project": scalar, "global": scalar, "adapter": scalar},
+			},
+		},
+		"adapters": {
+			kind: yaml.MappingNode,
+			values: &field{
+				kind:   yaml.MappingNode,
+				fields: map[string]*field{"output": scalar, "template": scalar},
+			},
+		},
+		"install_mode": {kind: yaml.ScalarNode, check: checkInstallMode},
+		"registry":     scalar,
+		"registries": {
+			kind: yaml.MappingNode,
+			values: &field{
+				kind: yaml.MappingNode,
+				fields: map[string]*field{
+					"namespace":  scalar,
+					"index":      scalar,
+					"plain_http": {kind: yaml.ScalarNode, check: checkBool},
+					"insecure":   {kind: yaml.ScalarNode, check: checkBool},
+					"ca_file":    scalar,
+					"cert_file":  scalar,
+					"key_file":   scalar,
+				},
+				check: checkRegistry,
+			},
+		},
+		"lint": {
+			kind:   yaml.MappingNode,
+			fields: map[string]*field{"disable": {kind: yaml.SequenceNode, values: scalar}},
+		},
+		"skills": {
+			kind:  yaml.SequenceNode,
+			match: matchSkill,
+			values: &field{
+				shorthand: "ref",
+				fields: map[string]*field{
+					"ref":      scalar,
+					"name":     scalar,
+					"agents":   {kind: yaml.SequenceNode, values: scalar},
+					"pin":      scalar,
+					"optional": {kind: yaml.ScalarNode, check: checkBool},
+					"verify":   scalar,
+				},
+				check: checkSkill,
+			},
+		},
+	},
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+):`)
+
+// Validate checks a config document for syntax errors, unknown keys, values of the wrong
+// type and invalid skill references. Problems are reported with their line and column.
+func Validate(data []byte) []Problem {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		line := 0
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return []Problem{{Line: line, Message: err.Error()}}
+	}
+
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	problems := schema.validate(doc.Content[0], "")
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+// ValidateFile validates the config file at path. See Validate.
+func ValidateFile(path string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	return Validate(data), nil
+}
+
+func (f *field) validate(node *yaml.Node, path string) []Problem {
+	// Empty values are always allowed
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	if f.kind != 0 && node.Kind != f.kind {
+		return []Problem{problemf(node, "%s: expected %s, got %s", describePath(path), kindName(f.kind), kindName(node.Kind))}
+	}
+
+	var problems []Problem
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinPath(path, key.Value)
+
+			child := f.values
+			if f.fields != nil {
+				known, ok := f.fields[key.Value]
+				if !ok {
+					p := problemf(key, "unknown key %q", childPath)
+					p.Warning = true
+					problems = append(problems, p)
+					continue
+				}
+				child = known
+			}
+			if child != nil {
+				problems = append(problems, child.validate(value, childPath)...)
+			}
+		}
+	case yaml.SequenceNode:
+		if f.values != nil {
+			for i, item := range node.Content {
+				problems = append(problems, f.values.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	if f.check != nil {
+		problems = append(problems, f.check(node)...)
+	}
+
+	return problems
+}
+
+func checkVersion(node *yaml.Node) []Problem {
+	version, err := strconv.Atoi(node.Value)
+	if err != nil || version < 1 {
+		return []Problem{problemf(node, "version must be a positive integer")}
+	}
+	if version > CurrentVersion {
+		return []Problem{problemf(node, "version %d is not supported (latest is %d)", version, CurrentVersion)}
+	}
+	return nil
+}
+
+func checkInstallMode(node *yaml.Node) []Problem {
+	switch InstallMode(node.Value) {
+	case InstallModeCopy, InstallModeSymlink:
+		return nil
+	}
+	return []Problem{problemf(node, "install_mode must be %q or %q", InstallModeCopy, InstallModeSymlink)}
+}
+
+func checkBool(node *yaml.Node) []Problem {
+	var b bool
+	if err := node.Decode(&b); err != nil {
+		return []Problem{problemf(node, "expected true or false, got %q", node.Value)}
+	}
+	return nil
+}
+
+func checkSkill(node *yaml.Node) []Problem {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if err := ValidateRef(node.Value); err != nil {
+			return []Problem{problemf(node, "%v", err)}
+		}
+		return nil
+	case yaml.MappingNode:
+		values := make(map[string]*yaml.Node)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			values[node.Content[i].Value] = node.Content[i+1]
+		}
+
+		var problems []Problem
+		if ref, ok := values["ref"]; !ok || ref.Value == "" {
+			problems = append(problems, problemf(node, "skill entry is missing a ref"))
+		} else if err := ValidateRef(ref.Value); err != nil {
+			problems = append(problems, problemf(ref, "%v", err))
+		}
+		if pin, ok := values["pin"]; ok && pin.Value != "" {
+			if _, err := digest.Parse(pin.Value); err != nil {
+				problems = append(problems, problemf(pin, "invalid pin %q: %v", pin.Value, err))
+			}
+		}
+		return problems
+	default:
+		return []Problem{problemf(node, "skill must be a reference or a mapping")}
+	}
+}
+
+// checkRegistry checks that a client certificate of a registry comes with its key.
+func checkRegistry(node *yaml.Node) []Problem {
+	values := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		values[node.Content[i].Value] = node.Content[i+1]
+	}
+	cert, key := values["cert_file"], values["key_file"]
+	switch {
+	case cert != nil && cert.Value != "" && (key == nil || key.Value == ""):
+		return []Problem{problemf(cert, "cert_file requires a key_file")}
+	case key != nil && key.Value != "" && (cert == nil || cert.Value == ""):
+		return []Problem{problemf(key, "key_file requires a cert_file")}
+	}
+	return nil
+}
+
+func matchSkill(item *yaml.Node, id string) bool {
+	var entry SkillEntry
+	if err := item.Decode(&entry); err != nil {
+		return false
+	}
+	return entry.Matches(id)
+}
+
+func problemf(node *yaml.Node, format string, args ...any) Problem {
+	return Problem{Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "config"
+	}
+	return path
+}
+
+func kindName(kind yaml.Kind) string {
+	switch kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.ScalarNode:
+		return "a value"
+	case yaml.AliasNode:
+		return "an alias"
+	default:
+		return "a document"
+	}
+}
@@ -0,0 +1,389 @@
+// Package diff compares two skill artifacts: their annotations, dependencies and files.
+package diff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andrewhowdencom/skr/pkg/skill"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pmezard/go-difflib/difflib"
+	"oras.land/oras-go/v2/content"
+)
+
+// maxTextFileSize is the largest file that is diffed line by line. Larger files are
+// compared by digest only.
+const maxTextFileSize = 1 << 20
+
+// File status in a Diff.
+const (
+	StatusAdded    = "added"
+	StatusRemoved  = "removed"
+	StatusModified = "modified"
+)
+
+// Artifact is the content of a skill artifact that is compared.
+type Artifact struct {
+	Ref         string
+	Digest      string
+	Annotations map[string]string
+	// Dependencies are the references from the dependencies annotation.
+	Dependencies []string
+	// Files are the entries of the skill layer, by path.
+	Files map[string]File
+}
+
+// File is an entry of the skill layer.
+type File struct {
+	store.FileInfo
+	// Content is the content of text files, or nil for other entries.
+	Content []byte
+}
+
+// Load reads the manifest of ref (resolved to desc) and its skill layer from fetcher, which
+// can be the local store or a remote repository.
+func Load(ctx context.Context, fetcher content.Fetcher, ref string, desc ocispec.Descriptor) (*Artifact, error) {
+	manifestBytes, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %s: %w", ref, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest for %s: %w", ref, err)
+	}
+
+	a := &Artifact{
+		Ref:         ref,
+		Digest:      desc.Digest.String(),
+		Annotations: manifest.Annotations,
+		Files:       make(map[string]File),
+	}
+	if deps, ok := manifest.Annotations[store.AnnotationDependencies]; ok {
+		if err := json.Unmarshal([]byte(deps), &a.Dependencies); err != nil {
+			return nil, fmt.Errorf("failed to parse dependencies of %s: %w", ref, err)
+		}
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != store.MediaTypeSkillLayer {
+			continue
+		}
+		if err := a.readLayer(ctx, fetcher, layer); err != nil {
+			return nil, fmt.Errorf("failed to read layer of %s: %w", ref, err)
+		}
+	}
+	return a, nil
+}
+
+func (a *Artifact) readLayer(ctx context.Context, fetcher content.Fetcher, layer ocispec.Descriptor) error {
+	rc, err := fetcher.Fetch(ctx, layer)
+	if err != nil {
+		return fmt.Errorf("failed to fetch layer: %w", err)
+	}
+	defer rc.Close()
+
+	vr := content.NewVerifyReader(rc, layer)
+	err = store.WalkLayer(vr, func(entry store.FileInfo, r io.Reader) error {
+		f := File{FileInfo: entry}
+		if entry.Type == "file" {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", entry.Path, err)
+			}
+			f.Digest = digest.FromBytes(data).String()
+			if len(data) <= maxTextFileSize && utf8.Valid(data) && !bytes.ContainsRune(data, 0) {
+				f.Content = data
+			}
+		}
+		a.Files[entry.Path] = f
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Read any padding after the end of the archive, so the digest can be verified
+	if _, err := io.Copy(io.Discard, vr); err != nil {
+		return fmt.Errorf("failed to read layer: %w", err)
+	}
+	return vr.Verify()
+}
+
+// Diff is the difference between two artifacts.
+type Diff struct {
+	From, To *Artifact
+	// Annotations are the changed annotations, sorted by key. Dependencies are reported
+	// separately.
+	Annotations []AnnotationChange
+	// AddedDependencies and RemovedDependencies are the references only in To or From.
+	AddedDependencies   []string
+	RemovedDependencies []string
+	// Files are the changed files, with SKILL.md first and the rest sorted by path.
+	Files []FileDiff
+}
+
+// AnnotationChange is an annotation that was added, removed or changed. Old or New is empty
+// if the annotation was added or removed.
+type AnnotationChange struct {
+	Key      string
+	Old, New string
+}
+
+// FileDiff is a file that was added, removed or changed.
+type FileDiff struct {
+	Path   string
+	Status string
+	// OldMode and NewMode are set if the permissions changed.
+	OldMode, NewMode string
+	// Binary is set if either version is not a text file, so there is no line diff.
+	Binary bool
+	// Unified is the unified diff of text files.
+	Unified    string
+	Insertions int
+	Deletions  int
+}
+
+// Empty reports whether the artifacts are the same.
+func (d *Diff) Empty() bool {
+	return len(d.Annotations) == 0 && len(d.AddedDependencies) == 0 && len(d.RemovedDependencies) == 0 && len(d.Files) == 0
+}
+
+// Compare returns the changes from one artifact to another.
+func Compare(from, to *Artifact) (*Diff, error) {
+	d := &Diff{From: from, To: to}
+
+	for _, key := range unionKeys(from.Annotations, to.Annotations) {
+		if key == store.AnnotationDependencies {
+			continue
+		}
+		before, beforeOK := from.Annotations[key]
+		after, afterOK := to.Annotations[key]
+		if before != after || beforeOK != afterOK {
+			d.Annotations = append(d.Annotations, AnnotationChange{Key: key, Old: before, New: after})
+		}
+	}
+
+	d.AddedDependencies = subtract(to.Dependencies, from.Dependencies)
+	d.RemovedDependencies = subtract(from.Dependencies, to.Dependencies)
+
+	paths := unionKeys(from.Files, to.Files)
+	sort.SliceStable(paths, func(i, j int) bool {
+		return paths[i] == skill.SkillFileName && paths[j] != skill.SkillFileName
+	})
+	for _, p := range paths {
+		before, beforeOK := from.Files[p]
+		after, afterOK := to.Files[p]
+		fd, err := compareFile(p, before, beforeOK, after, afterOK)
+		if err != nil {
+			return nil, err
+		}
+		if fd != nil {
+			d.Files = append(d.Files, *fd)
+		}
+	}
+	return d, nil
+}
+
+// compareFile returns the changes to the file at p, or nil if it did not change.
+func compareFile(p string, before File, beforeOK bool, after File, afterOK bool) (*FileDiff, error) {
+	fd := &FileDiff{Path: p, Status: StatusModified}
+	switch {
+	case (!beforeOK && after.Type == "dir") || (!afterOK && before.Type == "dir"):
+		// Added and removed directories show in the files they hold
+		return nil, nil
+	case !beforeOK:
+		fd.Status = StatusAdded
+	case !afterOK:
+		fd.Status = StatusRemoved
+	case before.Type == after.Type && before.Digest == after.Digest && before.Target == after.Target:
+		if before.Mode == after.Mode {
+			return nil, nil
+		}
+		fd.OldMode, fd.NewMode = before.Mode, after.Mode
+		return fd, nil
+	}
+	if beforeOK && afterOK && before.Mode != after.Mode {
+		fd.OldMode, fd.NewMode = before.Mode, after.Mode
+	}
+
+	// Only the content of regular files is diffed; a symlink is shown as its target
+	oldText, oldIsText := fileText(before, beforeOK)
+	newText, newIsText := fileText(after, afterOK)
+	if !oldIsText || !newIsText {
+		fd.Binary = true
+		return fd, nil
+	}
+
+	fromFile, toFile := "a/"+p, "b/"+p
+	if !beforeOK {
+		fromFile = "/dev/null"
+	}
+	if !afterOK {
+		toFile = "/dev/null"
+	}
+	a, b := splitLines(oldText), splitLines(newText)
+	unified, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        b,
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", p, err)
+	}
+	fd.Unified = unified
+
+	for _, op := range difflib.NewMatcher(a, b).GetOpCodes() {
+		if op.Tag == 'r' || op.Tag == 'd' {
+			fd.Deletions += op.I2 - op.I1
+		}
+		if op.Tag == 'r' || op.Tag == 'i' {
+			fd.Insertions += op.J2 - op.J1
+		}
+	}
+	return fd, nil
+}
+
+// fileText returns the text of f for a line diff. A missing file is empty. ok is false if
+// the file has content that cannot be diffed.
+func fileText(f File, exists bool) (text string, ok bool) {
+	switch {
+	case !exists, f.Type == "dir":
+		return "", true
+	case f.Type == "symlink":
+		return f.Target + "\n", true
+	case f.Content != nil || f.Size == 0:
+		return string(f.Content), true
+	}
+	return "", false
+}
+
+// Write prints the diff: the annotation and dependency changes, then a unified diff of
+// every changed file.
+func (d *Diff) Write(w io.Writer) {
+	fmt.Fprintf(w, "--- %s (%s)\n+++ %s (%s)\n", d.From.Ref, d.From.Digest, d.To.Ref, d.To.Digest)
+
+	if len(d.Annotations) > 0 {
+		fmt.Fprintln(w, "\nAnnotations:")
+		for _, c := range d.Annotations {
+			if c.Old != "" {
+				fmt.Fprintf(w, "- %s: %s\n", c.Key, c.Old)
+			}
+			if c.New != "" {
+				fmt.Fprintf(w, "+ %s: %s\n", c.Key, c.New)
+			}
+		}
+	}
+
+	if len(d.AddedDependencies) > 0 || len(d.RemovedDependencies) > 0 {
+		fmt.Fprintln(w, "\nDependencies:")
+		for _, dep := range d.RemovedDependencies {
+			fmt.Fprintf(w, "- %s\n", dep)
+		}
+		for _, dep := range d.AddedDependencies {
+			fmt.Fprintf(w, "+ %s\n", dep)
+		}
+	}
+
+	for _, f := range d.Files {
+		fmt.Fprintf(w, "\ndiff %s (%s)\n", f.Path, f.Status)
+		if f.OldMode != f.NewMode {
+			fmt.Fprintf(w, "mode %s -> %s\n", f.OldMode, f.NewMode)
+		}
+		if f.Binary {
+			fmt.Fprintln(w, "Binary files differ")
+		}
+		fmt.Fprint(w, f.Unified)
+	}
+}
+
+// WriteStat prints a summary of the changed files, with the number of inserted and deleted
+// lines.
+func (d *Diff) WriteStat(w io.Writer) {
+	width := 0
+	for _, f := range d.Files {
+		width = max(width, len(f.Path))
+	}
+
+	var insertions, deletions int
+	for _, f := range d.Files {
+		switch {
+		case f.Binary:
+			fmt.Fprintf(w, " %-*s | Bin\n", width, f.Path)
+		case f.Insertions+f.Deletions == 0:
+			fmt.Fprintf(w, " %-*s | %s\n", width, f.Path, f.Status)
+		default:
+			fmt.Fprintf(w, " %-*s | %4d %s%s\n", width, f.Path, f.Insertions+f.Deletions,
+				strings.Repeat("+", min(f.Insertions, 40)), strings.Repeat("-", min(f.Deletions, 40)))
+		}
+		insertions += f.Insertions
+		deletions += f.Deletions
+	}
+	fmt.Fprintf(w, " %d files changed, %d insertions(+), %d deletions(-)", len(d.Files), insertions, deletions)
+	if n := len(d.Annotations); n > 0 {
+		fmt.Fprintf(w, ", %d annotations changed", n)
+	}
+	if n := len(d.AddedDependencies) + len(d.RemovedDependencies); n > 0 {
+		fmt.Fprintf(w, ", %d dependencies changed", n)
+	}
+	fmt.Fprintln(w)
+}
+
+// unionKeys returns the keys of both maps, sorted.
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var keys []string
+	for _, m := range []map[string]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// noNewline marks a last line without a newline, like diff(1).
+const noNewline = "\\ No newline at end of file\n"
+
+// splitLines splits text into lines for difflib, which expects every line to end with a
+// newline. A last line without one is marked with noNewline, so that a change to the
+// trailing newline shows in the diff.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := difflib.SplitLines(strings.TrimSuffix(text, "\n"))
+	if !strings.HasSuffix(text, "\n") {
+		lines[len(lines)-1] += noNewline
+	}
+	return lines
+}
+
+// subtract returns the elements of a that are not in b.
+func subtract(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		found := false
+		for _, t := range b {
+			if s == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, s)
+		}
+	}
+	return out
+}
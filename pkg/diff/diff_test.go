@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+
+	build := func(tag string, files map[string]string) *Artifact {
+		srcDir := filepath.Join(t.TempDir(), "pdf")
+		for p, data := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(srcDir, p)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, p), []byte(data), 0644))
+		}
+		ref := "localhost/skills/pdf:" + tag
+		require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+
+		desc, err := st.Resolve(ctx, ref)
+		require.NoError(t, err)
+		a, err := Load(ctx, st, ref, desc)
+		require.NoError(t, err)
+		return a
+	}
+
+	v1 := build("v1", map[string]string{
+		"SKILL.md":            "---\nname: pdf\ndescription: Work with PDFs\ndependencies:\n  - localhost/skills/ocr:v1\n---\n# PDF\n\nExtract text.\n",
+		"references/guide.md": "# Guide\n",
+		"scripts/run.sh":      "echo run\n",
+		"assets/logo.bin":     "\x00\x01",
+	})
+	v2 := build("v2", map[string]string{
+		"SKILL.md":            "---\nname: pdf\ndescription: Work with PDF files\ndependencies:\n  - localhost/skills/ocr:v2\n---\n# PDF\n\nExtract text and tables.\n",
+		"references/guide.md": "# Guide\n",
+		"references/api.md":   "# API\n",
+		"assets/logo.bin":     "\x00\x02",
+	})
+
+	d, err := Compare(v1, v2)
+	require.NoError(t, err)
+	assert.False(t, d.Empty())
+
+	var keys []string
+	for _, c := range d.Annotations {
+		keys = append(keys, c.Key)
+	}
+	assert.Contains(t, keys, "org.opencontainers.image.description")
+	assert.NotContains(t, keys, store.AnnotationDependencies)
+	assert.Equal(t, []string{"localhost/skills/ocr:v2"}, d.AddedDependencies)
+	assert.Equal(t, []string{"localhost/skills/ocr:v1"}, d.RemovedDependencies)
+
+	var paths []string
+	for _, f := range d.Files {
+		paths = append(paths, f.Path+" "+f.Status)
+	}
+	assert.Equal(t, []string{
+		"SKILL.md modified",
+		"assets/logo.bin modified",
+		"references/api.md added",
+		"scripts/run.sh removed",
+	}, paths)
+
+	skillMD := d.Files[0]
+	assert.Equal(t, 3, skillMD.Insertions)
+	assert.Equal(t, 3, skillMD.Deletions)
+	assert.Contains(t, skillMD.Unified, "--- a/SKILL.md\n+++ b/SKILL.md\n")
+	assert.Contains(t, skillMD.Unified, "-Extract text.\n+Extract text and tables.\n")
+
+	for _, f := range d.Files {
+		switch f.Path {
+		case "assets/logo.bin":
+			assert.True(t, f.Binary)
+		case "references/api.md":
+			assert.Equal(t, "--- /dev/null\n+++ b/references/api.md\n@@ -0,0 +1 @@\n+# API\n", f.Unified)
+		}
+	}
+
+	var stat bytes.Buffer
+	d.WriteStat(&stat)
+	assert.Contains(t, stat.String(), " SKILL.md          |    6 +++---\n")
+	assert.Contains(t, stat.String(), " assets/logo.bin   | Bin\n")
+	assert.Contains(t, stat.String(), " 4 files changed, 4 insertions(+), 4 deletions(-), 1 annotations changed, 2 dependencies changed\n")
+
+	same, err := Compare(v1, v1)
+	require.NoError(t, err)
+	assert.True(t, same.Empty())
+}
+
+func TestCompareFileTrailingNewline(t *testing.T) {
+	before := File{FileInfo: store.FileInfo{Type: "file", Mode: "0644", Digest: "sha256:a", Size: 6}, Content: []byte("# PDF\n")}
+	after := File{FileInfo: store.FileInfo{Type: "file", Mode: "0644", Digest: "sha256:b", Size: 5}, Content: []byte("# PDF")}
+
+	fd, err := compareFile("SKILL.md", before, true, after, true)
+	require.NoError(t, err)
+	require.NotNil(t, fd)
+	assert.Equal(t, 1, fd.Insertions)
+	assert.Equal(t, 1, fd.Deletions)
+	assert.Contains(t, fd.Unified, "-# PDF\n+# PDF\n\\ No newline at end of file\n")
+}
@@ -37,6 +37,42 @@ func GetHeadTags() ([]string, error) {
 	return tags, nil
 }
 
+// GetRevision returns the full SHA of HEAD in the repository containing dir (or the
+// current directory, if dir is empty)
+func GetRevision(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// GetRemoteURL returns the URL of the origin remote of the repository containing dir (or
+// the current directory, if dir is empty). GitHub SSH remotes are converted to the HTTPS
+// URL of the repository, which is what registries such as GHCR link to.
+func GetRemoteURL(dir string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	// git@github.com:user/repo.git -> https://github.com/user/repo
+	url := strings.TrimSpace(out.String())
+	if strings.HasPrefix(url, "git@github.com:") {
+		url = strings.Replace(url, "git@github.com:", "https://github.com/", 1)
+		url = strings.TrimSuffix(url, ".git")
+	} else if strings.HasPrefix(url, "https://github.com/") {
+		url = strings.TrimSuffix(url, ".git")
+	}
+	return url, nil
+}
+
 // ChangedFiles returns a list of files changed between HEAD and baseRef
 func ChangedFiles(baseRef string) ([]string, error) {
 	cmd := exec.Command("git", "diff", "--name-only", baseRef)
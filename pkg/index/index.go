@@ -0,0 +1,341 @@
+// Package index defines the search index of a skills registry: a single JSON document
+// describing every skill, so that clients can search a registry without listing it.
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andrewhowdencom/skr/pkg/skill"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"oras.land/oras-go/v2/content"
+)
+
+// FileName is the name the index is published under, next to the registry's /v2/ API.
+const FileName = "index.json"
+
+// Version is the version of the index format.
+const Version = 1
+
+// ErrNotSkill is returned by NewSkill for repositories that do not hold skills.
+var ErrNotSkill = errors.New("not a skill")
+
+// Index is the search index of a registry.
+type Index struct {
+	Version int `json:"version"`
+	// Registry is the host the skills are installed from. It may be empty, in which case
+	// clients use the host the index was fetched from.
+	Registry string  `json:"registry,omitempty"`
+	Skills   []Skill `json:"skills"`
+}
+
+// Skill describes a single repository.
+type Skill struct {
+	// Repository is the name of the repository, relative to the registry.
+	Repository  string `json:"repository"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Author      string `json:"author,omitempty"`
+	// Version is the version of the latest release, from its metadata.
+	Version string `json:"version,omitempty"`
+	// Latest is the tag to install by default.
+	Latest       string         `json:"latest"`
+	Dependencies []string       `json:"dependencies,omitempty"`
+	Extensions   map[string]any `json:"extensions,omitempty"`
+	Versions     []Release      `json:"versions"`
+	// Tokens are the distinct words of the SKILL.md body of the latest release, lowercased
+	// and sorted, for full-text search. They are only set if the index was built with
+	// Options.Content.
+	Tokens []string `json:"tokens,omitempty"`
+}
+
+// Options controls what NewSkill records.
+type Options struct {
+	// Content indexes the SKILL.md body, which requires fetching the skill layer.
+	Content bool
+}
+
+// Release is a single tag of a repository.
+type Release struct {
+	Tag     string    `json:"tag"`
+	Digest  string    `json:"digest"`
+	Created time.Time `json:"created,omitzero"`
+}
+
+// Ref returns the reference to install the latest release of s from registry.
+func (s Skill) Ref(registry string) string {
+	repo := s.Repository
+	if registry != "" {
+		repo = registry + "/" + repo
+	}
+	return repo + ":" + s.Latest
+}
+
+// Matches reports whether every term of query appears in the name, repository, description
+// or author of s, or starts a word of its SKILL.md body, ignoring case.
+func (s Skill) Matches(query string) bool {
+	text := strings.ToLower(strings.Join([]string{s.Name, s.Repository, s.Description, s.Author}, "\n"))
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, term) && !s.hasToken(term) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasToken reports whether any token starts with prefix.
+func (s Skill) hasToken(prefix string) bool {
+	i := sort.SearchStrings(s.Tokens, prefix)
+	return i < len(s.Tokens) && strings.HasPrefix(s.Tokens[i], prefix)
+}
+
+// Tokenize splits text into its distinct words, lowercased and sorted. Words of a single
+// character are dropped.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, w := range words {
+		if utf8.RuneCountInString(w) < 2 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		tokens = append(tokens, w)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// Search returns the skills matching query, sorted by name.
+func (i *Index) Search(query string) []Skill {
+	var results []Skill
+	for _, s := range i.Skills {
+		if s.Matches(query) {
+			results = append(results, s)
+		}
+	}
+	sort.SliceStable(results, func(a, b int) bool { return results[a].Name < results[b].Name })
+	return results
+}
+
+// Sort orders the skills by repository, so that the index is stable.
+func (i *Index) Sort() {
+	sort.Slice(i.Skills, func(a, b int) bool { return i.Skills[a].Repository < i.Skills[b].Repository })
+}
+
+// Read decodes an index.
+func Read(r io.Reader) (*Index, error) {
+	var idx Index
+	if err := json.NewDecoder(r).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	if idx.Version > Version {
+		return nil, fmt.Errorf("index version %d is not supported (latest is %d)", idx.Version, Version)
+	}
+	return &idx, nil
+}
+
+// Fetch reads the index at location, which is either an HTTP(S) URL or a local path. If the
+// index does not name a registry, the host of the URL is used.
+func Fetch(ctx context.Context, location string) (*Index, error) {
+	u, err := url.Parse(location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open index: %w", err)
+		}
+		defer f.Close()
+		return Read(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index URL %s: %w", location, err)
+	}
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch index: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch index: %s", resp.Status)
+	}
+
+	idx, err := Read(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if idx.Registry == "" {
+		idx.Registry = u.Host
+	}
+	return idx, nil
+}
+
+// NewSkill describes the skill in repository repo. tags maps each tag to the descriptor of
+// its manifest; the metadata of the skill is read from the latest one (see LatestTag).
+func NewSkill(ctx context.Context, fetcher content.Fetcher, repo string, tags map[string]ocispec.Descriptor, opts Options) (*Skill, error) {
+	s := &Skill{
+		Repository: repo,
+		Name:       repo[strings.LastIndex(repo, "/")+1:],
+	}
+
+	manifests := make(map[string]ocispec.Manifest)
+	for tag, desc := range tags {
+		manifest, err := fetchManifest(ctx, fetcher, desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s:%s: %w", repo, tag, err)
+		}
+		manifests[tag] = manifest
+
+		release := Release{Tag: tag, Digest: desc.Digest.String()}
+		if created, err := time.Parse(time.RFC3339, manifest.Annotations[ocispec.AnnotationCreated]); err == nil {
+			release.Created = created
+		}
+		s.Versions = append(s.Versions, release)
+	}
+	sort.Slice(s.Versions, func(i, j int) bool { return s.Versions[i].Tag < s.Versions[j].Tag })
+
+	s.Latest = LatestTag(s.Versions)
+	if manifests[s.Latest].Config.MediaType != store.MediaTypeSkillConfig {
+		return nil, ErrNotSkill
+	}
+	annotations := manifests[s.Latest].Annotations
+	if title := annotations[ocispec.AnnotationTitle]; title != "" {
+		s.Name = title
+	}
+	s.Description = store.Annotation(annotations, ocispec.AnnotationDescription)
+	s.Author = store.Annotation(annotations, ocispec.AnnotationAuthors)
+	s.Version = store.Annotation(annotations, ocispec.AnnotationVersion)
+	if deps, ok := annotations[store.AnnotationDependencies]; ok {
+		_ = json.Unmarshal([]byte(deps), &s.Dependencies)
+	}
+	if extensions, ok := annotations[store.AnnotationExtensions]; ok {
+		_ = json.Unmarshal([]byte(extensions), &s.Extensions)
+	}
+
+	if opts.Content {
+		body, err := readBody(ctx, fetcher, manifests[s.Latest])
+		if err != nil {
+			return nil, fmt.Errorf("failed to index %s:%s: %w", repo, s.Latest, err)
+		}
+		s.Tokens = Tokenize(body)
+	}
+	return s, nil
+}
+
+// readBody returns the SKILL.md body of the skill with manifest.
+func readBody(ctx context.Context, fetcher content.Fetcher, manifest ocispec.Manifest) (string, error) {
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != store.MediaTypeSkillLayer {
+			continue
+		}
+		rc, err := fetcher.Fetch(ctx, layer)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch layer: %w", err)
+		}
+		defer rc.Close()
+
+		data, err := store.ReadLayerFile(rc, skill.SkillFileName)
+		if err != nil {
+			return "", err
+		}
+		fm, err := skill.ParseFrontmatter(data)
+		if err != nil {
+			return "", err
+		}
+		return string(fm.Body), nil
+	}
+	return "", fmt.Errorf("no skill layer")
+}
+
+// FromStore builds the index of every skill in the local store. Repositories that do not
+// hold skills, or that cannot be read, are left out.
+func FromStore(ctx context.Context, st *store.Store, opts Options) (*Index, error) {
+	refs, err := st.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skills from store: %w", err)
+	}
+
+	repos := make(map[string]map[string]ocispec.Descriptor)
+	for _, ref := range refs {
+		repo, tag := SplitRef(ref)
+		desc, err := st.Resolve(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+		}
+		if repos[repo] == nil {
+			repos[repo] = make(map[string]ocispec.Descriptor)
+		}
+		repos[repo][tag] = desc
+	}
+
+	idx := &Index{Version: Version, Skills: []Skill{}}
+	for repo, tags := range repos {
+		s, err := NewSkill(ctx, st, repo, tags, opts)
+		if errors.Is(err, ErrNotSkill) {
+			continue
+		}
+		if err != nil {
+			// One broken skill (e.g. a truncated blob) shouldn't hide every other one
+			slog.Warn("failed to index repository, skipping", "repository", repo, "error", err)
+			continue
+		}
+		idx.Skills = append(idx.Skills, *s)
+	}
+	idx.Sort()
+	return idx, nil
+}
+
+// SplitRef splits a tagged reference into its repository and tag. References without a
+// tag are given "latest".
+func SplitRef(ref string) (repo, tag string) {
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, "latest"
+}
+
+// LatestTag picks the tag to install by default: "latest" if there is one, or else the most
+// recently created release (or the last tag, if no release records when it was created).
+func LatestTag(releases []Release) string {
+	var latest Release
+	for _, r := range releases {
+		if r.Tag == "latest" {
+			return r.Tag
+		}
+		if latest.Tag == "" || !r.Created.Before(latest.Created) {
+			latest = r
+		}
+	}
+	return latest.Tag
+}
+
+func fetchManifest(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) (ocispec.Manifest, error) {
+	var manifest ocispec.Manifest
+	data, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,154 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSkill(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+
+	srcDir := filepath.Join(t.TempDir(), "pdf")
+	content := `---
+name: pdf
+description: Extract text and tables from PDF files
+dependencies:
+  - example.com/skills/ocr:v1
+metadata:
+  author: Jane Doe
+  version: 1.2.0
+---
+# PDF
+
+Use pdfplumber to extract tables.
+`
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+
+	tags := make(map[string]ocispec.Descriptor)
+	for _, tag := range []string{"1.2.0", "latest"} {
+		ref := "example.com/skills/pdf:" + tag
+		require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+		desc, err := st.Resolve(ctx, ref)
+		require.NoError(t, err)
+		tags[tag] = desc
+	}
+
+	s, err := NewSkill(ctx, st, "skills/pdf", tags, Options{Content: true})
+	require.NoError(t, err)
+	assert.Equal(t, "pdf", s.Name)
+	assert.Equal(t, "Extract text and tables from PDF files", s.Description)
+	assert.Equal(t, "Jane Doe", s.Author)
+	assert.Equal(t, "1.2.0", s.Version)
+	assert.Equal(t, "latest", s.Latest)
+	assert.Equal(t, []string{"example.com/skills/ocr:v1"}, s.Dependencies)
+	require.Len(t, s.Versions, 2)
+	assert.Equal(t, "1.2.0", s.Versions[0].Tag)
+	assert.Equal(t, tags["1.2.0"].Digest.String(), s.Versions[0].Digest)
+	assert.False(t, s.Versions[0].Created.IsZero())
+	assert.Equal(t, "example.com/skills/pdf:latest", s.Ref("example.com"))
+	assert.Equal(t, []string{"extract", "pdf", "pdfplumber", "tables", "to", "use"}, s.Tokens)
+}
+
+func TestFromStore(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+
+	for _, name := range []string{"docx", "pdf"} {
+		srcDir := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		content := fmt.Sprintf("---\nname: %s\ndescription: test skill\n---\n# %s\n", name, name)
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+		require.NoError(t, st.Build(ctx, srcDir, "localhost:5000/skills/"+name+":v1", nil))
+	}
+
+	idx, err := FromStore(ctx, st, Options{})
+	require.NoError(t, err)
+	require.Len(t, idx.Skills, 2)
+	assert.Equal(t, "localhost:5000/skills/docx", idx.Skills[0].Repository)
+	assert.Equal(t, "v1", idx.Skills[0].Latest)
+	assert.Equal(t, "pdf", idx.Skills[1].Name)
+	assert.Empty(t, idx.Skills[1].Tokens)
+}
+
+func TestFromStoreCorruptSkill(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	st, err := store.New(dir)
+	require.NoError(t, err)
+
+	for _, name := range []string{"docx", "pdf"} {
+		srcDir := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		content := fmt.Sprintf("---\nname: %s\ndescription: test skill\n---\n# %s\n", name, name)
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+		require.NoError(t, st.Build(ctx, srcDir, "localhost:5000/skills/"+name+":v1", nil))
+	}
+
+	// Truncate the manifest of one skill
+	desc, err := st.Resolve(ctx, "localhost:5000/skills/pdf:v1")
+	require.NoError(t, err)
+	blob := filepath.Join(dir, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	require.NoError(t, os.Chmod(blob, 0644))
+	require.NoError(t, os.WriteFile(blob, []byte("{"), 0644))
+
+	idx, err := FromStore(ctx, st, Options{Content: true})
+	require.NoError(t, err)
+	require.Len(t, idx.Skills, 1)
+	assert.Equal(t, "localhost:5000/skills/docx", idx.Skills[0].Repository)
+}
+
+func TestSearch(t *testing.T) {
+	idx := &Index{Version: Version, Skills: []Skill{
+		{Repository: "skills/pdf", Name: "pdf", Description: "Extract text from PDF files", Author: "Jane Doe"},
+		{Repository: "skills/docx", Name: "docx", Description: "Edit Word documents", Author: "John Doe"},
+		{Repository: "tools/git", Name: "git", Description: "Work with git repositories", Tokens: Tokenize("Run `git rebase` carefully.")},
+	}}
+
+	names := func(skills []Skill) []string {
+		var names []string
+		for _, s := range skills {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"pdf"}, names(idx.Search("pdf")))
+	assert.Equal(t, []string{"docx", "pdf"}, names(idx.Search("DOE")))
+	assert.Equal(t, []string{"pdf"}, names(idx.Search("jane text")))
+	assert.Equal(t, []string{"docx", "pdf"}, names(idx.Search("skills/")))
+	assert.Empty(t, idx.Search("jane word"))
+
+	// Words of the body match by prefix
+	assert.Equal(t, []string{"git"}, names(idx.Search("rebas")))
+	assert.Empty(t, idx.Search("ebase"))
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"add", "git", "go", "rebase", "run", "then", "v2"}, Tokenize("Run `git rebase`, then: go add v2 a GIT"))
+}
+
+func TestRead(t *testing.T) {
+	_, err := Read(strings.NewReader(fmt.Sprintf(`{"version": %d, "skills": []}`, Version+1)))
+	assert.ErrorContains(t, err, "not supported")
+
+	idx, err := Read(strings.NewReader(`{"version": 1, "skills": [{"repository": "skills/pdf", "name": "pdf", "latest": "v1"}]}`))
+	require.NoError(t, err)
+	require.Len(t, idx.Skills, 1)
+	assert.Equal(t, "skills/pdf:v1", idx.Skills[0].Ref(""))
+}
@@ -0,0 +1,195 @@
+// Package lint checks Agent Skills against the specification and a set of best practices.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/andrewhowdencom/skr/pkg/skill"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is how serious a finding is. The values match the SARIF result levels.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+)
+
+// Finding is a single problem reported by a rule.
+type Finding struct {
+	RuleID   string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// File is the path of the file the finding is about, relative to the skill directory.
+	File string `json:"file"`
+	// Line is the 1-based line in File, or 0 if the finding is about the file as a whole.
+	Line int `json:"line,omitempty"`
+	// Column is the 1-based column in Line, or 0 if it is not known.
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	if f.Line > 0 && f.Column > 0 {
+		location = fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", location, f.Severity, f.Message, f.RuleID)
+}
+
+// Rule is a single lint check.
+type Rule struct {
+	ID          string
+	Description string
+	// Severity is the severity of the rule's findings, unless the rule sets one explicitly.
+	Severity Severity
+	check    func(t *Target) []Finding
+}
+
+// Target is a skill being linted.
+type Target struct {
+	// Dir is the skill directory.
+	Dir string
+	// Content is the raw content of SKILL.md.
+	Content []byte
+	// Skill is the parsed skill.
+	Skill *skill.Skill
+	// Frontmatter is the frontmatter mapping, with line numbers relative to SKILL.md.
+	Frontmatter *yaml.Node
+}
+
+// Options controls which rules are run.
+type Options struct {
+	// Disable lists the IDs of rules that are not run.
+	Disable []string
+}
+
+// Lint runs every enabled rule against the skill at dir. An error is only returned if
+// the skill could not be read at all; a SKILL.md that cannot be parsed is reported as a
+// finding of the frontmatter rule.
+func Lint(dir string, opts Options) ([]Finding, error) {
+	for _, id := range opts.Disable {
+		if _, ok := RuleByID(id); !ok {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+	}
+
+	// Resolve the directory so that "." can be compared with the skill name
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(absDir, skill.SkillFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", skill.SkillFileName, err)
+	}
+
+	t := &Target{Dir: absDir, Content: content}
+	if findings := t.parse(); len(findings) > 0 {
+		// Nothing else can be checked without the frontmatter
+		return findings, nil
+	}
+
+	var findings []Finding
+	for _, rule := range Rules {
+		if slices.Contains(opts.Disable, rule.ID) {
+			continue
+		}
+		for _, f := range rule.check(t) {
+			f.RuleID = rule.ID
+			if f.Severity == "" {
+				f.Severity = rule.Severity
+			}
+			if f.File == "" {
+				f.File = skill.SkillFileName
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// HasErrors reports whether any of the findings is an error.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// parse reads the frontmatter and body of the target's SKILL.md.
+func (t *Target) parse() []Finding {
+	fm, err := skill.ParseFrontmatter(t.Content)
+	if err != nil {
+		return []Finding{parseFinding(err)}
+	}
+
+	doc, err := fm.Node()
+	if err != nil {
+		return []Finding{parseFinding(err)}
+	}
+	if len(doc.Content) > 0 {
+		t.Frontmatter = doc.Content[0]
+	}
+
+	s, err := skill.LoadUnverified(t.Dir)
+	if err != nil {
+		// Values of the wrong type are reported more precisely by the schema
+		if findings := checkFrontmatter(t); len(findings) > 0 {
+			for i := range findings {
+				findings[i].RuleID = "frontmatter"
+				findings[i].Severity = SeverityError
+				findings[i].File = skill.SkillFileName
+			}
+			return findings
+		}
+		return []Finding{parseFinding(err)}
+	}
+	t.Skill = s
+	return nil
+}
+
+// parseFinding reports a SKILL.md that cannot be parsed, at the position of the problem if known.
+func parseFinding(err error) Finding {
+	f := Finding{RuleID: "frontmatter", Severity: SeverityError, File: skill.SkillFileName, Line: 1, Message: err.Error()}
+
+	var perr *skill.ParseError
+	if errors.As(err, &perr) {
+		f.Line = perr.Line
+		f.Column = perr.Column
+		f.Message = perr.Message
+	}
+	return f
+}
+
+// frontmatterLine returns the line of key in the frontmatter, or 1 if it is not set.
+func (t *Target) frontmatterLine(key string) int {
+	if t.Frontmatter == nil {
+		return 1
+	}
+	for i := 0; i+1 < len(t.Frontmatter.Content); i += 2 {
+		if t.Frontmatter.Content[i].Value == key {
+			return t.Frontmatter.Content[i].Line
+		}
+	}
+	return 1
+}
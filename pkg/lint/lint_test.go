@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSkill(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+}
+
+func rules(findings []Finding) map[string]int {
+	ids := make(map[string]int)
+	for _, f := range findings {
+		ids[f.RuleID]++
+	}
+	return ids
+}
+
+func TestLint(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-skill")
+	writeSkill(t, dir, `---
+name: other-name
+description: A skill for testing the linter.
+colour: blue
+dependencies:
+  - "ghcr.io/example/INVALID:v1"
+---
+# My Skill
+
+Read [the guide](references/guide.md) and [the missing guide](references/missing.md#usage),
+see [the docs](https://example.com/docs) or [escape](../outside.md).
+
+Run `+"`scripts/run.sh`"+` and then scripts/missing.sh.
+
+`+"```"+`
+[in a fence](not-checked.md)
+`+"```"+`
+`)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "references"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "references", "guide.md"), []byte("# Guide\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("#!/bin/sh\n"), 0644))
+
+	findings, err := Lint(dir, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{
+		"name-matches-directory": 1,
+		"unknown-frontmatter":    1,
+		"dependency-refs":        1,
+		"relative-links":         2,
+		"referenced-files":       1,
+		"scripts-executable":     1,
+	}, rules(findings))
+	assert.True(t, HasErrors(findings))
+
+	for _, f := range findings {
+		switch f.RuleID {
+		case "unknown-frontmatter":
+			assert.Equal(t, 4, f.Line)
+		case "referenced-files":
+			assert.Equal(t, 13, f.Line)
+			assert.Contains(t, f.Message, "scripts/missing.sh")
+		case "scripts-executable":
+			assert.Equal(t, "scripts/run.sh", f.File)
+		}
+	}
+
+	// Rules can be disabled
+	findings, err = Lint(dir, Options{Disable: []string{"relative-links", "referenced-files", "dependency-refs"}})
+	require.NoError(t, err)
+	assert.False(t, HasErrors(findings))
+
+	_, err = Lint(dir, Options{Disable: []string{"no-such-rule"}})
+	assert.Error(t, err)
+}
+
+func TestLint_Body(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "empty")
+	writeSkill(t, dir, "---\nname: empty\ndescription: An empty skill.\n---\n")
+
+	findings, err := Lint(dir, Options{})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "body", findings[0].RuleID)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+
+	writeSkill(t, dir, "---\nname: empty\ndescription: An empty skill.\n---\n# Empty\n\nTODO\n")
+	findings, err = Lint(dir, Options{})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityWarning, findings[0].Severity)
+}
@@ -0,0 +1,312 @@
+package lint
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/andrewhowdencom/skr/pkg/config"
+	"github.com/andrewhowdencom/skr/pkg/skill"
+)
+
+// Size budgets for a skill. Agents load SKILL.md into their context, and install the
+// whole skill, so both should stay small.
+const (
+	MaxBodyLines  = 500
+	MaxFileSize   = 1 << 20  // 1 MiB
+	MaxTotalSize  = 10 << 20 // 10 MiB
+	MinBodyLength = 50
+)
+
+// Rules are all lint rules, in the order they are run.
+var Rules = []Rule{
+	{
+		ID:          "frontmatter",
+		Description: "SKILL.md has frontmatter that matches the frontmatter schema",
+		Severity:    SeverityError,
+		check:       checkFrontmatter,
+	},
+	{
+		ID:          "name-matches-directory",
+		Description: "The skill name matches the name of its directory",
+		Severity:    SeverityWarning,
+		check:       checkNameMatchesDirectory,
+	},
+	{
+		ID:          "body",
+		Description: "SKILL.md has a markdown body with instructions",
+		Severity:    SeverityError,
+		check:       checkBody,
+	},
+	{
+		ID:          "relative-links",
+		Description: "Relative links in SKILL.md resolve to files in the skill",
+		Severity:    SeverityError,
+		check:       checkRelativeLinks,
+	},
+	{
+		ID:          "referenced-files",
+		Description: "Files under scripts/ and references/ mentioned in SKILL.md exist",
+		Severity:    SeverityError,
+		check:       checkReferencedFiles,
+	},
+	{
+		ID:          "unknown-frontmatter",
+		Description: "The frontmatter only uses keys defined by the specification",
+		Severity:    SeverityNote,
+		check:       checkUnknownFrontmatter,
+	},
+	{
+		ID:          "dependency-refs",
+		Description: "Dependencies are valid OCI references",
+		Severity:    SeverityError,
+		check:       checkDependencyRefs,
+	},
+	{
+		ID:          "size",
+		Description: fmt.Sprintf("The SKILL.md body is at most %d lines, files at most %d bytes, and the skill at most %d bytes", MaxBodyLines, MaxFileSize, MaxTotalSize),
+		Severity:    SeverityWarning,
+		check:       checkSize,
+	},
+	{
+		ID:          "scripts-executable",
+		Description: "Files in scripts/ are executable",
+		Severity:    SeverityWarning,
+		check:       checkScriptsExecutable,
+	},
+}
+
+// RuleByID looks up a rule.
+func RuleByID(id string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+func checkFrontmatter(t *Target) []Finding {
+	errs, err := skill.ValidateFrontmatter(t.Content)
+	if err != nil {
+		return []Finding{parseFinding(err)}
+	}
+
+	var findings []Finding
+	for _, e := range errs {
+		findings = append(findings, Finding{Line: e.Line, Column: e.Column, Message: e.Error()})
+	}
+	return findings
+}
+
+func checkNameMatchesDirectory(t *Target) []Finding {
+	dirName := filepath.Base(t.Dir)
+	if t.Skill.Name != "" && t.Skill.Name != dirName {
+		return []Finding{{
+			Line:    t.frontmatterLine("name"),
+			Message: fmt.Sprintf("name %q does not match the directory name %q", t.Skill.Name, dirName),
+		}}
+	}
+	return nil
+}
+
+func checkBody(t *Target) []Finding {
+	text := strings.TrimSpace(t.Skill.Body)
+	if text == "" {
+		return []Finding{{Line: t.Skill.BodyLine, Message: "SKILL.md has no instructions after the frontmatter"}}
+	}
+
+	// Headings alone do not tell the agent anything
+	length := 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		length += len(strings.TrimFunc(line, unicode.IsSpace))
+	}
+	if length < MinBodyLength {
+		return []Finding{{
+			Severity: SeverityWarning,
+			Line:     t.Skill.BodyLine,
+			Message:  fmt.Sprintf("SKILL.md has very little instruction text (%d characters)", length),
+		}}
+	}
+	return nil
+}
+
+// markdownLink matches inline links and images, capturing the target.
+var markdownLink = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+func checkRelativeLinks(t *Target) []Finding {
+	var findings []Finding
+	forEachBodyLine(t, func(line int, text string) {
+		for _, m := range markdownLink.FindAllStringSubmatch(text, -1) {
+			target := m[1]
+			if isExternal(target) {
+				continue
+			}
+			if f := t.checkPath(line, target); f != nil {
+				findings = append(findings, *f)
+			}
+		}
+	})
+	return findings
+}
+
+// referencedPath matches paths into scripts/ or references/ that are not link targets.
+var referencedPath = regexp.MustCompile("(?:^|[\\s`'\"])((?:\\./)?(?:" + skill.ScriptsDir + "|references)/[\\w./-]+)")
+
+func checkReferencedFiles(t *Target) []Finding {
+	var findings []Finding
+	forEachBodyLine(t, func(line int, text string) {
+		for _, m := range referencedPath.FindAllStringSubmatch(text, -1) {
+			path := strings.TrimRight(m[1], ".,:;")
+			if f := t.checkPath(line, path); f != nil {
+				findings = append(findings, *f)
+			}
+		}
+	})
+	return findings
+}
+
+func checkUnknownFrontmatter(t *Target) []Finding {
+	if t.Frontmatter == nil {
+		return nil
+	}
+
+	known := skill.FrontmatterSchema().Properties
+
+	var findings []Finding
+	for i := 0; i+1 < len(t.Frontmatter.Content); i += 2 {
+		key := t.Frontmatter.Content[i]
+		if _, ok := known[key.Value]; !ok {
+			findings = append(findings, Finding{
+				Line:    key.Line,
+				Message: fmt.Sprintf("frontmatter key %q is not defined by the specification (it is kept as an extension)", key.Value),
+			})
+		}
+	}
+	return findings
+}
+
+func checkDependencyRefs(t *Target) []Finding {
+	var findings []Finding
+	line := t.frontmatterLine("dependencies")
+	for _, dep := range t.Skill.Dependencies {
+		if err := config.ValidateRef(dep); err != nil {
+			findings = append(findings, Finding{Line: line, Message: fmt.Sprintf("dependency: %v", err)})
+		}
+	}
+	return findings
+}
+
+func checkSize(t *Target) []Finding {
+	var findings []Finding
+
+	if lines := strings.Count(t.Skill.Body, "\n"); lines > MaxBodyLines {
+		findings = append(findings, Finding{
+			Line:    t.Skill.BodyLine,
+			Message: fmt.Sprintf("SKILL.md body has %d lines (budget %d); move details into references/", lines, MaxBodyLines),
+		})
+	}
+
+	var total int64
+	err := filepath.WalkDir(t.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		if info.Size() > MaxFileSize {
+			relPath, _ := filepath.Rel(t.Dir, path)
+			findings = append(findings, Finding{
+				File:    filepath.ToSlash(relPath),
+				Message: fmt.Sprintf("file is %d bytes (budget %d)", info.Size(), MaxFileSize),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		findings = append(findings, Finding{Message: fmt.Sprintf("failed to scan skill: %v", err)})
+	}
+
+	if total > MaxTotalSize {
+		findings = append(findings, Finding{
+			File:    ".",
+			Message: fmt.Sprintf("skill is %d bytes in total (budget %d)", total, MaxTotalSize),
+		})
+	}
+	return findings
+}
+
+func checkScriptsExecutable(t *Target) []Finding {
+	scripts, err := skill.NonExecutableScripts(t.Dir)
+	if err != nil {
+		return []Finding{{Message: err.Error()}}
+	}
+
+	var findings []Finding
+	for _, script := range scripts {
+		findings = append(findings, Finding{File: script, Message: "script is not executable"})
+	}
+	return findings
+}
+
+// forEachBodyLine calls fn with each line of the body and its line number in SKILL.md,
+// skipping fenced code blocks.
+func forEachBodyLine(t *Target, fn func(line int, text string)) {
+	inFence := false
+	for i, text := range strings.Split(t.Skill.Body, "\n") {
+		trimmed := strings.TrimSpace(text)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		fn(t.Skill.BodyLine+i, text)
+	}
+}
+
+// checkPath reports a finding if path, relative to the skill directory, does not exist or
+// is outside the skill.
+func (t *Target) checkPath(line int, target string) *Finding {
+	// Drop any fragment or query
+	if i := strings.IndexAny(target, "#?"); i != -1 {
+		target = target[:i]
+	}
+	if target == "" {
+		return nil
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	if filepath.IsAbs(target) || !filepath.IsLocal(filepath.FromSlash(target)) {
+		return &Finding{Line: line, Message: fmt.Sprintf("%s points outside the skill", target)}
+	}
+	if _, err := os.Stat(filepath.Join(t.Dir, filepath.FromSlash(target))); err != nil {
+		return &Finding{Line: line, Message: fmt.Sprintf("%s does not exist", target)}
+	}
+	return nil
+}
+
+func isExternal(target string) bool {
+	if strings.HasPrefix(target, "#") || strings.HasPrefix(target, "//") {
+		return true
+	}
+	u, err := url.Parse(target)
+	return err == nil && u.Scheme != ""
+}
@@ -0,0 +1,242 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	orasregistry "oras.land/oras-go/v2/registry"
+)
+
+// CopyOptions configures Copy.
+type CopyOptions struct {
+	// Recursive also copies the dependencies of the skill, and theirs, to the namespace of
+	// the destination, and rewrites the dependencies to refer to the copies.
+	Recursive bool
+}
+
+// CopyResult is an artifact copied by Copy.
+type CopyResult struct {
+	Source      string
+	Destination string
+	Descriptor  ocispec.Descriptor
+	// Rewritten reports whether the dependencies of the artifact were rewritten. Its digest
+	// changed then, so its referrers (such as signatures) were not copied.
+	Rewritten bool
+}
+
+// Copy copies the skill src from its registry to dst, along with its referrers (such as
+// signatures and SBOMs). If dst has neither a tag nor a digest, the tag of src is used.
+//
+// With Recursive, the dependencies are copied first, from wherever they are, to the
+// namespace of dst: dependencies in the namespace of src keep their path below it, and
+// others keep the last element of their repository. For example, copying
+// ghcr.io/acme/staging/pdf:v1 to ghcr.io/acme/prod/pdf:v1 copies its dependency
+// ghcr.io/acme/staging/tools/ocr:v2 to ghcr.io/acme/prod/tools/ocr:v2, and
+// docker.io/other/fonts:v1 to ghcr.io/acme/prod/fonts:v1.
+//
+// The results list the dependencies before the skills that depend on them.
+func Copy(ctx context.Context, src, dst string, opts CopyOptions) ([]CopyResult, error) {
+	c := &copier{
+		opts: opts,
+		target: func(ref string) (oras.GraphTarget, error) {
+			return newRepository(ref)
+		},
+	}
+	return c.copy(ctx, src, dst)
+}
+
+// copier copies artifacts between the targets returned by target, for the repository of
+// a reference.
+type copier struct {
+	opts   CopyOptions
+	target func(ref string) (oras.GraphTarget, error)
+
+	srcNamespace, dstNamespace string
+	done                       map[string]CopyResult
+	visiting                   map[string]bool
+	results                    []CopyResult
+}
+
+func (c *copier) copy(ctx context.Context, src, dst string) ([]CopyResult, error) {
+	srcRef, err := orasregistry.ParseReference(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %s: %w", src, err)
+	}
+	dstRef, err := orasregistry.ParseReference(dst)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %s: %w", dst, err)
+	}
+	if srcRef.Reference == "" {
+		srcRef.Reference = "latest"
+	}
+	if dstRef.Reference == "" {
+		dstRef.Reference = srcRef.Reference
+	}
+
+	c.srcNamespace, c.dstNamespace = namespace(srcRef), namespace(dstRef)
+	c.done = make(map[string]CopyResult)
+	c.visiting = make(map[string]bool)
+	c.results = nil
+	if _, err := c.copyArtifact(ctx, srcRef, dstRef); err != nil {
+		return nil, err
+	}
+	return c.results, nil
+}
+
+// copyArtifact copies src to dst, after its dependencies if the copy is recursive.
+func (c *copier) copyArtifact(ctx context.Context, src, dst orasregistry.Reference) (CopyResult, error) {
+	key := src.String()
+	if result, ok := c.done[key]; ok {
+		return result, nil
+	}
+	if c.visiting[key] {
+		return CopyResult{}, fmt.Errorf("circular dependency on %s", key)
+	}
+	c.visiting[key] = true
+	defer delete(c.visiting, key)
+
+	srcTarget, err := c.target(key)
+	if err != nil {
+		return CopyResult{}, err
+	}
+	dstTarget, err := c.target(dst.String())
+	if err != nil {
+		return CopyResult{}, err
+	}
+
+	desc, err := srcTarget.Resolve(ctx, src.Reference)
+	if err != nil {
+		return CopyResult{}, fmt.Errorf("failed to resolve %s: %w", key, err)
+	}
+	manifestBytes, err := content.FetchAll(ctx, srcTarget, desc)
+	if err != nil {
+		return CopyResult{}, fmt.Errorf("failed to fetch manifest of %s: %w", key, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return CopyResult{}, fmt.Errorf("failed to parse manifest of %s: %w", key, err)
+	}
+
+	var deps, rewritten []string
+	if depsJSON, ok := manifest.Annotations[store.AnnotationDependencies]; ok && c.opts.Recursive {
+		if err := json.Unmarshal([]byte(depsJSON), &deps); err != nil {
+			return CopyResult{}, fmt.Errorf("failed to parse dependencies of %s: %w", key, err)
+		}
+		for _, dep := range deps {
+			depSrc, err := orasregistry.ParseReference(dep)
+			if err != nil {
+				return CopyResult{}, fmt.Errorf("invalid dependency %s of %s: %w", dep, key, err)
+			}
+			if depSrc.Reference == "" {
+				depSrc.Reference = "latest"
+			}
+			result, err := c.copyArtifact(ctx, depSrc, c.rewrite(depSrc))
+			if err != nil {
+				return CopyResult{}, err
+			}
+			rewritten = append(rewritten, result.Destination)
+		}
+	}
+
+	result := CopyResult{Source: key, Descriptor: desc}
+	if slices.Equal(deps, rewritten) {
+		// Unchanged, so the referrers of the manifest still apply to the copy
+		if _, err := oras.ExtendedCopy(ctx, srcTarget, src.Reference, dstTarget, dst.Reference, oras.DefaultExtendedCopyOptions); err != nil {
+			return CopyResult{}, fmt.Errorf("failed to copy %s to %s: %w", key, dst, err)
+		}
+	} else {
+		if desc, err = c.pushRewritten(ctx, srcTarget, dstTarget, desc, manifestBytes, rewritten); err != nil {
+			return CopyResult{}, fmt.Errorf("failed to copy %s to %s: %w", key, dst, err)
+		}
+		result.Descriptor, result.Rewritten = desc, true
+
+		// A digest refers to the rewritten manifest
+		if dst.ValidateReferenceAsDigest() == nil {
+			dst.Reference = desc.Digest.String()
+		} else if err := dstTarget.Tag(ctx, desc, dst.Reference); err != nil {
+			return CopyResult{}, fmt.Errorf("failed to tag %s: %w", dst, err)
+		}
+	}
+
+	result.Destination = dst.String()
+
+	c.done[key] = result
+	c.results = append(c.results, result)
+	return result, nil
+}
+
+// pushRewritten copies the manifest desc, with its dependencies replaced by deps, and its
+// blobs from src to dst.
+func (c *copier) pushRewritten(ctx context.Context, src, dst oras.GraphTarget, desc ocispec.Descriptor, manifestBytes []byte, deps []string) (ocispec.Descriptor, error) {
+	// Rewrite the manifest generically, so that no field is lost
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var annotations map[string]string
+	if err := json.Unmarshal(manifest["annotations"], &annotations); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	depsJSON, err := json.Marshal(deps)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	annotations[store.AnnotationDependencies] = string(depsJSON)
+	if manifest["annotations"], err = json.Marshal(annotations); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	rewritten, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	successors, err := content.Successors(ctx, src, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	for _, successor := range successors {
+		if err := oras.CopyGraph(ctx, src, dst, successor, oras.DefaultCopyGraphOptions); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	newDesc := content.NewDescriptorFromBytes(desc.MediaType, rewritten)
+	newDesc.ArtifactType = desc.ArtifactType
+	if err := dst.Push(ctx, newDesc, bytes.NewReader(rewritten)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return newDesc, nil
+}
+
+// rewrite returns the reference in the destination namespace of the dependency ref.
+func (c *copier) rewrite(ref orasregistry.Reference) orasregistry.Reference {
+	repo := ref.Registry + "/" + ref.Repository
+	rel, ok := strings.CutPrefix(repo, c.srcNamespace+"/")
+	if !ok {
+		rel = path.Base(ref.Repository)
+	}
+
+	dstRegistry, dstPath, _ := strings.Cut(c.dstNamespace, "/")
+	return orasregistry.Reference{
+		Registry:   dstRegistry,
+		Repository: strings.TrimPrefix(dstPath+"/"+rel, "/"),
+		Reference:  ref.Reference,
+	}
+}
+
+// namespace returns the registry and repository of ref, without its last element.
+func namespace(ref orasregistry.Reference) string {
+	if dir := path.Dir(ref.Repository); dir != "." {
+		return ref.Registry + "/" + dir
+	}
+	return ref.Registry
+}
@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	orasregistry "oras.land/oras-go/v2/registry"
+)
+
+// memoryRegistries holds one in-memory target per repository.
+type memoryRegistries map[string]*memory.Store
+
+func (m memoryRegistries) target(ref string) (oras.GraphTarget, error) {
+	r, err := orasregistry.ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	repo := r.Registry + "/" + r.Repository
+	if m[repo] == nil {
+		m[repo] = memory.New()
+	}
+	return m[repo], nil
+}
+
+// pushSkill pushes a skill with deps to ref, and returns its descriptor.
+func (m memoryRegistries) pushSkill(t *testing.T, ref string, deps ...string) ocispec.Descriptor {
+	ctx := context.Background()
+	target, err := m.target(ref)
+	require.NoError(t, err)
+
+	layer, err := oras.PushBytes(ctx, target, store.MediaTypeSkillLayer, []byte(ref))
+	require.NoError(t, err)
+	config, err := oras.PushBytes(ctx, target, store.MediaTypeSkillConfig, []byte("{}"))
+	require.NoError(t, err)
+	annotations := map[string]string{ocispec.AnnotationTitle: ref}
+	if len(deps) > 0 {
+		depsJSON, err := json.Marshal(deps)
+		require.NoError(t, err)
+		annotations[store.AnnotationDependencies] = string(depsJSON)
+	}
+	desc, err := oras.PackManifest(ctx, target, oras.PackManifestVersion1_1, store.ArtifactType, oras.PackManifestOptions{
+		Layers:              []ocispec.Descriptor{layer},
+		ConfigDescriptor:    &config,
+		ManifestAnnotations: annotations,
+	})
+	require.NoError(t, err)
+
+	r, err := orasregistry.ParseReference(ref)
+	require.NoError(t, err)
+	require.NoError(t, target.Tag(ctx, desc, r.Reference))
+	// Registries resolve every manifest by digest
+	require.NoError(t, target.Tag(ctx, desc, desc.Digest.String()))
+	return desc
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	registries := memoryRegistries{}
+
+	fonts := registries.pushSkill(t, "docker.io/other/fonts:v1")
+	registries.pushSkill(t, "ghcr.io/acme/staging/tools/ocr:v2", "docker.io/other/fonts@"+fonts.Digest.String())
+	pdf := registries.pushSkill(t, "ghcr.io/acme/staging/pdf:v1", "ghcr.io/acme/staging/tools/ocr:v2", "docker.io/other/fonts:v1")
+
+	// A signature of pdf
+	staging, err := registries.target("ghcr.io/acme/staging/pdf")
+	require.NoError(t, err)
+	signature, err := oras.PackManifest(ctx, staging, oras.PackManifestVersion1_1, "application/vnd.example.signature", oras.PackManifestOptions{Subject: &pdf})
+	require.NoError(t, err)
+
+	t.Run("referrers", func(t *testing.T) {
+		c := &copier{target: registries.target}
+		results, err := c.copy(ctx, "ghcr.io/acme/staging/pdf:v1", "ghcr.io/acme/prod/pdf")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "ghcr.io/acme/prod/pdf:v1", results[0].Destination)
+		assert.Equal(t, pdf.Digest, results[0].Descriptor.Digest)
+		assert.False(t, results[0].Rewritten)
+
+		prod := registries["ghcr.io/acme/prod/pdf"]
+		desc, err := prod.Resolve(ctx, "v1")
+		require.NoError(t, err)
+		assert.Equal(t, pdf.Digest, desc.Digest)
+		exists, err := prod.Exists(ctx, signature)
+		require.NoError(t, err)
+		assert.True(t, exists, "signature is copied")
+
+		// Dependencies are not copied
+		assert.Nil(t, registries["ghcr.io/acme/prod/tools/ocr"])
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		c := &copier{opts: CopyOptions{Recursive: true}, target: registries.target}
+		results, err := c.copy(ctx, "ghcr.io/acme/staging/pdf:v1", "ghcr.io/acme/release/pdf:v1")
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		// Dependencies come first, and are only copied once
+		assert.Equal(t, "docker.io/other/fonts@"+fonts.Digest.String(), results[0].Source)
+		assert.Equal(t, "ghcr.io/acme/release/fonts@"+fonts.Digest.String(), results[0].Destination)
+		assert.False(t, results[0].Rewritten)
+		assert.Equal(t, "ghcr.io/acme/staging/tools/ocr:v2", results[1].Source)
+		assert.Equal(t, "ghcr.io/acme/release/tools/ocr:v2", results[1].Destination)
+		assert.True(t, results[1].Rewritten)
+		assert.Equal(t, "ghcr.io/acme/release/fonts:v1", results[2].Destination)
+		assert.Equal(t, "ghcr.io/acme/release/pdf:v1", results[3].Destination)
+		assert.True(t, results[3].Rewritten)
+		assert.NotEqual(t, pdf.Digest, results[3].Descriptor.Digest)
+
+		release := registries["ghcr.io/acme/release/pdf"]
+		desc, err := release.Resolve(ctx, "v1")
+		require.NoError(t, err)
+		assert.Equal(t, results[3].Descriptor.Digest, desc.Digest)
+
+		data, err := content.FetchAll(ctx, release, desc)
+		require.NoError(t, err)
+		var manifest ocispec.Manifest
+		require.NoError(t, json.Unmarshal(data, &manifest))
+		var deps []string
+		require.NoError(t, json.Unmarshal([]byte(manifest.Annotations[store.AnnotationDependencies]), &deps))
+		assert.Equal(t, []string{"ghcr.io/acme/release/tools/ocr:v2", "ghcr.io/acme/release/fonts:v1"}, deps)
+		assert.Equal(t, "ghcr.io/acme/staging/pdf:v1", manifest.Annotations[ocispec.AnnotationTitle], "other annotations are kept")
+		for _, blob := range append(manifest.Layers, manifest.Config) {
+			exists, err := release.Exists(ctx, blob)
+			require.NoError(t, err)
+			assert.True(t, exists)
+		}
+
+		// The signature no longer applies to the rewritten manifest
+		exists, err := release.Exists(ctx, signature)
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		// fonts has no dependencies, so its digest is kept, and ocr still refers to it by digest
+		fontsDesc, err := registries["ghcr.io/acme/release/fonts"].Resolve(ctx, "v1")
+		require.NoError(t, err)
+		assert.Equal(t, fonts.Digest, fontsDesc.Digest)
+		data, err = content.FetchAll(ctx, registries["ghcr.io/acme/release/tools/ocr"], results[1].Descriptor)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &manifest))
+		assert.Equal(t, `["ghcr.io/acme/release/fonts@`+fonts.Digest.String()+`"]`, manifest.Annotations[store.AnnotationDependencies])
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		registries.pushSkill(t, "localhost/a:v1", "localhost/b:v1")
+		registries.pushSkill(t, "localhost/b:v1", "localhost/a:v1")
+		c := &copier{opts: CopyOptions{Recursive: true}, target: registries.target}
+		_, err := c.copy(ctx, "localhost/a:v1", "localhost/prod/a:v1")
+		assert.ErrorContains(t, err, "circular dependency")
+	})
+}
@@ -0,0 +1,138 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Artifact describes a skill artifact in a remote registry.
+type Artifact struct {
+	// Descriptor is the descriptor of the manifest.
+	Descriptor ocispec.Descriptor
+	Manifest   ocispec.Manifest
+	// Config is the decoded config blob, or nil if the artifact has no skill config.
+	Config *store.SkillConfig
+	// Tags are all the tags in the repository, not only those of this artifact.
+	Tags []string
+	// TagsErr is set if the tags could not be listed, e.g. because the credentials are
+	// scoped to the repository.
+	TagsErr error
+	// Referrers are the artifacts (e.g. signatures or SBOMs) that refer to the manifest.
+	Referrers []ocispec.Descriptor
+	// ReferrersErr is set if the referrers could not be listed.
+	ReferrersErr error
+
+	repo *remote.Repository
+}
+
+// Size is the total size of the artifact: the manifest, config and layers.
+func (a *Artifact) Size() int64 {
+	size := a.Descriptor.Size + a.Manifest.Config.Size
+	for _, layer := range a.Manifest.Layers {
+		size += layer.Size
+	}
+	return size
+}
+
+// Inspect fetches the manifest and config of ref, along with the tags of its repository and
+// its referrers. Layers are not downloaded. If ref has neither a tag nor a digest, "latest"
+// is used. Failures to list the tags or referrers are recorded in the Artifact, rather than
+// returned.
+func Inspect(ctx context.Context, ref string) (*Artifact, error) {
+	repo, err := newRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Reference.Reference == "" {
+		repo.Reference.Reference = "latest"
+	}
+
+	desc, rc, err := repo.FetchReference(ctx, repo.Reference.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %s: %w", ref, err)
+	}
+	manifestBytes, err := content.ReadAll(rc, desc)
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest for %s: %w", ref, err)
+	}
+
+	a := &Artifact{Descriptor: desc, repo: repo}
+	if err := json.Unmarshal(manifestBytes, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest for %s: %w", ref, err)
+	}
+
+	if a.Manifest.Config.MediaType == store.MediaTypeSkillConfig {
+		configBytes, err := content.FetchAll(ctx, repo, a.Manifest.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch config for %s: %w", ref, err)
+		}
+		var cfg store.SkillConfig
+		if err := json.Unmarshal(configBytes, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config for %s: %w", ref, err)
+		}
+		a.Config = &cfg
+	}
+
+	// Not every registry (or token) allows listing tags or referrers; the manifest is still
+	// worth reporting
+	err = repo.Tags(ctx, "", func(tags []string) error {
+		a.Tags = append(a.Tags, tags...)
+		return nil
+	})
+	if err != nil {
+		a.Tags, a.TagsErr = nil, fmt.Errorf("failed to list tags for %s: %w", ref, err)
+	}
+
+	err = repo.Referrers(ctx, desc, "", func(referrers []ocispec.Descriptor) error {
+		a.Referrers = append(a.Referrers, referrers...)
+		return nil
+	})
+	if err != nil {
+		a.Referrers, a.ReferrersErr = nil, fmt.Errorf("failed to list referrers for %s: %w", ref, err)
+	}
+
+	return a, nil
+}
+
+// Files streams the skill layer of the artifact and lists its entries. Nothing is written to
+// the store.
+func (a *Artifact) Files(ctx context.Context) ([]store.FileInfo, error) {
+	var layer *ocispec.Descriptor
+	for i := range a.Manifest.Layers {
+		if a.Manifest.Layers[i].MediaType == store.MediaTypeSkillLayer {
+			layer = &a.Manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, fmt.Errorf("artifact has no skill layer")
+	}
+
+	rc, err := a.repo.Fetch(ctx, *layer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch layer: %w", err)
+	}
+	defer rc.Close()
+
+	vr := content.NewVerifyReader(rc, *layer)
+	files, err := store.ReadLayerFiles(vr)
+	if err != nil {
+		return nil, err
+	}
+	// Read any padding after the end of the archive, so the digest can be verified
+	if _, err := io.Copy(io.Discard, vr); err != nil {
+		return nil, fmt.Errorf("failed to read layer: %w", err)
+	}
+	if err := vr.Verify(); err != nil {
+		return nil, fmt.Errorf("failed to verify layer: %w", err)
+	}
+	return files, nil
+}
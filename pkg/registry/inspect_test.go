@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRegistry serves a single repository over the distribution API.
+type testRegistry struct {
+	blobs     map[digest.Digest][]byte
+	manifests map[string]ocispec.Descriptor
+	tags      []string
+	referrers []ocispec.Descriptor
+	// forbidden refuses to list tags and referrers, like a token scoped to the repository.
+	forbidden bool
+}
+
+func (r *testRegistry) add(mediaType string, data []byte) ocispec.Descriptor {
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	r.blobs[desc.Digest] = data
+	return desc
+}
+
+func (r *testRegistry) addManifest(t *testing.T, manifest ocispec.Manifest, tag string) ocispec.Descriptor {
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	desc := r.add(ocispec.MediaTypeImageManifest, data)
+	desc.ArtifactType = manifest.ArtifactType
+	r.manifests[desc.Digest.String()] = desc
+	if tag != "" {
+		r.manifests[tag] = desc
+		r.tags = append(r.tags, tag)
+	}
+	return desc
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.Path, "/v2/skills/pdf/")
+	serve := func(desc ocispec.Descriptor, ok bool) {
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", desc.MediaType)
+		w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+		w.Header().Set("Content-Length", strconv.FormatInt(desc.Size, 10))
+		if req.Method != http.MethodHead {
+			w.Write(r.blobs[desc.Digest])
+		}
+	}
+
+	switch {
+	case req.URL.Path == "/v2/":
+	case strings.HasPrefix(path, "manifests/"):
+		desc, ok := r.manifests[strings.TrimPrefix(path, "manifests/")]
+		serve(desc, ok)
+	case strings.HasPrefix(path, "blobs/"):
+		d := digest.Digest(strings.TrimPrefix(path, "blobs/"))
+		data, ok := r.blobs[d]
+		serve(ocispec.Descriptor{MediaType: "application/octet-stream", Digest: d, Size: int64(len(data))}, ok)
+	case r.forbidden:
+		w.WriteHeader(http.StatusForbidden)
+	case path == "tags/list":
+		json.NewEncoder(w).Encode(map[string]any{"name": "skills/pdf", "tags": r.tags})
+	case strings.HasPrefix(path, "referrers/"):
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+		json.NewEncoder(w).Encode(ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}, MediaType: ocispec.MediaTypeImageIndex, Manifests: r.referrers})
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// uncompressedLayer returns a skill layer with SKILL.md, stored without compression so that
+// layers of content of the same length have the same size.
+func uncompressedLayer(t *testing.T, skillMD string) []byte {
+	var buf bytes.Buffer
+	gzw, err := gzip.NewWriterLevel(&buf, gzip.NoCompression)
+	require.NoError(t, err)
+	tw := tar.NewWriter(gzw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "SKILL.md", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(skillMD))}))
+	_, err = tw.Write([]byte(skillMD))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func TestInspect(t *testing.T) {
+	ctx := context.Background()
+
+	reg := &testRegistry{blobs: make(map[digest.Digest][]byte), manifests: make(map[string]ocispec.Descriptor)}
+	layer := reg.add(store.MediaTypeSkillLayer, uncompressedLayer(t, "---\nname: pdf\n---\n"))
+	config := reg.add(store.MediaTypeSkillConfig, []byte(`{"name":"pdf","description":"Read PDF files"}`))
+	desc := reg.addManifest(t, ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: store.ArtifactType,
+		Config:       config,
+		Layers:       []ocispec.Descriptor{layer},
+		Annotations:  map[string]string{ocispec.AnnotationTitle: "pdf"},
+	}, "v1")
+	reg.tags = append(reg.tags, "v2")
+	signature := reg.addManifest(t, ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/vnd.example.signature",
+		Config:       ocispec.DescriptorEmptyJSON,
+		Layers:       []ocispec.Descriptor{},
+		Subject:      &desc,
+	}, "")
+	reg.referrers = []ocispec.Descriptor{signature}
+
+	server := httptest.NewServer(reg)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	Configure(HostOptions{}, map[string]HostOptions{host: {PlainHTTP: true}})
+	defer Configure(HostOptions{}, nil)
+	ref := host + "/skills/pdf:v1"
+
+	a, err := Inspect(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, desc.Digest, a.Descriptor.Digest)
+	assert.Equal(t, "pdf", a.Manifest.Annotations[ocispec.AnnotationTitle])
+	require.NotNil(t, a.Config)
+	assert.Equal(t, "Read PDF files", a.Config.Description)
+	assert.Equal(t, []string{"v1", "v2"}, a.Tags)
+	assert.NoError(t, a.TagsErr)
+	require.Len(t, a.Referrers, 1)
+	assert.Equal(t, signature.Digest, a.Referrers[0].Digest)
+	assert.NoError(t, a.ReferrersErr)
+
+	files, err := a.Files(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "SKILL.md", files[0].Path)
+
+	t.Run("tampered layer", func(t *testing.T) {
+		original := reg.blobs[layer.Digest]
+		defer func() { reg.blobs[layer.Digest] = original }()
+		reg.blobs[layer.Digest] = uncompressedLayer(t, "---\nname: pwn\n---\n")
+
+		_, err := a.Files(ctx)
+		assert.ErrorContains(t, err, "failed to verify layer")
+	})
+
+	t.Run("listing forbidden", func(t *testing.T) {
+		reg.forbidden = true
+		defer func() { reg.forbidden = false }()
+
+		a, err := Inspect(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, desc.Digest, a.Descriptor.Digest)
+		require.NotNil(t, a.Config)
+		assert.Empty(t, a.Tags)
+		assert.Error(t, a.TagsErr)
+		assert.Empty(t, a.Referrers)
+		assert.Error(t, a.ReferrersErr)
+	})
+}
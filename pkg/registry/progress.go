@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+)
+
+// EventKind is the kind of a progress Event.
+type EventKind int
+
+const (
+	// EventStart is sent before a blob is transferred.
+	EventStart EventKind = iota
+	// EventProgress is sent while a blob is transferred, with the bytes transferred so far.
+	EventProgress
+	// EventDone is sent once a blob is transferred.
+	EventDone
+	// EventSkipped is sent for a blob that is already at the destination.
+	EventSkipped
+)
+
+// Event is the progress of the transfer of a blob (a manifest, config or layer).
+type Event struct {
+	Kind       EventKind
+	Descriptor ocispec.Descriptor
+	// Transferred is the number of bytes transferred so far, for EventProgress and EventDone.
+	Transferred int64
+}
+
+// Stats summarizes a transfer. Add every Event to it.
+type Stats struct {
+	// Transferred and Bytes count the blobs that were transferred.
+	Transferred int
+	Bytes       int64
+	// Skipped counts the blobs that were already at the destination.
+	Skipped int
+}
+
+// Add records e.
+func (s *Stats) Add(e Event) {
+	switch e.Kind {
+	case EventDone:
+		s.Transferred++
+		s.Bytes += e.Descriptor.Size
+	case EventSkipped:
+		s.Skipped++
+	}
+}
+
+// Option configures Push and Pull.
+type Option func(*options)
+
+type options struct {
+	progress func(Event)
+}
+
+// WithProgress calls fn with the progress of every blob. Blobs are transferred concurrently,
+// but fn is called for one event at a time.
+func WithProgress(fn func(Event)) Option {
+	return func(o *options) {
+		var mu sync.Mutex
+		o.progress = func(e Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(e)
+		}
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// copyOptions returns the options to copy to dst with, and the target to copy to, which
+// reports the progress of pushed blobs.
+func (o options) copyOptions(dst oras.Target) (oras.CopyOptions, oras.Target) {
+	opts := oras.DefaultCopyOptions
+	if o.progress == nil {
+		return opts, dst
+	}
+
+	opts.PreCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		o.progress(Event{Kind: EventStart, Descriptor: desc})
+		return nil
+	}
+	opts.PostCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		o.progress(Event{Kind: EventDone, Descriptor: desc, Transferred: desc.Size})
+		return nil
+	}
+	opts.OnCopySkipped = func(ctx context.Context, desc ocispec.Descriptor) error {
+		o.progress(Event{Kind: EventSkipped, Descriptor: desc})
+		return nil
+	}
+	return opts, &progressTarget{Target: dst, progress: o.progress}
+}
+
+// progressTarget reports the progress of the blobs pushed to Target.
+type progressTarget struct {
+	oras.Target
+	progress func(Event)
+}
+
+func (t *progressTarget) Push(ctx context.Context, expected ocispec.Descriptor, r io.Reader) error {
+	return t.Target.Push(ctx, expected, &progressReader{r: r, desc: expected, progress: t.progress})
+}
+
+// progressReader reports the bytes read from r.
+type progressReader struct {
+	r        io.Reader
+	desc     ocispec.Descriptor
+	progress func(Event)
+	n        int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.n += int64(n)
+		r.progress(Event{Kind: EventProgress, Descriptor: r.desc, Transferred: r.n})
+	}
+	return n, err
+}
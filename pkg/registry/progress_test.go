@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestProgress(t *testing.T) {
+	ctx := context.Background()
+	registries := memoryRegistries{}
+	registries.pushSkill(t, "localhost/pdf:v1")
+	src := registries["localhost/pdf"]
+
+	var events []Event
+	var stats Stats
+	opts := newOptions([]Option{WithProgress(func(e Event) {
+		events = append(events, e)
+		stats.Add(e)
+	})})
+
+	dst := memory.New()
+	copyOpts, target := opts.copyOptions(dst)
+	_, err := oras.Copy(ctx, src, "v1", target, "v1", copyOpts)
+	require.NoError(t, err)
+
+	// Manifest, config and layer
+	assert.Equal(t, 3, stats.Transferred)
+	assert.Zero(t, stats.Skipped)
+	kinds := make(map[EventKind]int)
+	var total int64
+	for _, e := range events {
+		kinds[e.Kind]++
+		if e.Kind == EventDone {
+			total += e.Descriptor.Size
+			assert.Equal(t, e.Descriptor.Size, e.Transferred)
+		}
+	}
+	assert.Equal(t, 3, kinds[EventStart])
+	assert.GreaterOrEqual(t, kinds[EventProgress], 3)
+	assert.Equal(t, total, stats.Bytes)
+
+	// Another skill shares the config, which is skipped
+	events, stats = nil, Stats{}
+	registries.pushSkill(t, "localhost/docx:v1")
+	_, err = oras.Copy(ctx, registries["localhost/docx"], "v1", target, "docx", copyOpts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Transferred, "manifest and layer")
+	assert.Equal(t, 1, stats.Skipped, "config")
+
+	// Without a progress function, the target is not wrapped
+	_, plain := newOptions(nil).copyOptions(dst)
+	assert.Same(t, dst, plain)
+}
@@ -4,22 +4,40 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	skrauth "github.com/andrewhowdencom/skr/pkg/auth"
 	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"oras.land/oras-go/v2/registry/remote/credentials"
 	"oras.land/oras-go/v2/registry/remote/retry"
 )
 
-// Push uploads a skill artifact from the local store to a remote registry.
-func Push(ctx context.Context, st *store.Store, ref string) error {
+// newRepository returns a client for the repository of ref, using the credentials from the
+// keyring and the options of its host (see Configure).
+func newRepository(ref string) (*remote.Repository, error) {
 	repo, err := remote.NewRepository(ref)
 	if err != nil {
-		return fmt.Errorf("invalid reference %s: %w", ref, err)
+		return nil, fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+	opts := optionsFor(repo.Reference.Registry)
+	repo.PlainHTTP = opts.PlainHTTP
+	if repo.Client, err = newClient(opts); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", repo.Reference.Registry, err)
+	}
+	return repo, nil
+}
+
+// newClient returns an HTTP client for registries, using the credentials from the keyring.
+func newClient(opts HostOptions) (*auth.Client, error) {
+	transport, err := opts.transport()
+	if err != nil {
+		return nil, err
 	}
 
 	// Instrument HTTP Client
@@ -28,7 +46,7 @@ func Push(ctx context.Context, st *store.Store, ref string) error {
 	// so that each retry attempt is traced (if we want detailed view) or
 	// wrap the retry transport (if we want one span per logical operation).
 	// Here we wrap the base transport to see network calls.
-	baseTransport := otelhttp.NewTransport(http.DefaultTransport)
+	baseTransport := otelhttp.NewTransport(transport)
 	retryTransport := retry.NewTransport(baseTransport)
 	httpClient := &http.Client{
 		Transport: retryTransport,
@@ -37,10 +55,36 @@ func Push(ctx context.Context, st *store.Store, ref string) error {
 	// Find credentials for the registry
 	// ORAS client automatically uses the credential store helper if configured.
 	// We inject our custom store backed by keyring.
-	repo.Client = &auth.Client{
+	return &auth.Client{
 		Client:     httpClient,
 		Cache:      auth.DefaultCache,
 		Credential: credentials.Credential(skrauth.NewStore()), // Wraps Store into CredentialFunc
+	}, nil
+}
+
+// Resolve returns the repository of ref, to fetch content from, and the descriptor of the
+// manifest that ref points to. If ref has neither a tag nor a digest, "latest" is used.
+func Resolve(ctx context.Context, ref string) (content.Fetcher, ocispec.Descriptor, error) {
+	repo, err := newRepository(ref)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, err
+	}
+	if repo.Reference.Reference == "" {
+		repo.Reference.Reference = "latest"
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return repo, desc, nil
+}
+
+// Push uploads a skill artifact from the local store to a remote registry.
+func Push(ctx context.Context, st *store.Store, ref string, opts ...Option) error {
+	repo, err := newRepository(ref)
+	if err != nil {
+		return err
 	}
 
 	// 2. Resolve Local Artifact
@@ -50,7 +94,8 @@ func Push(ctx context.Context, st *store.Store, ref string) error {
 	}
 
 	// 3. Copy from Local Store to Remote Repo
-	_, err = oras.Copy(ctx, st, ref, repo, ref, oras.DefaultCopyOptions)
+	copyOpts, dst := newOptions(opts).copyOptions(repo)
+	_, err = oras.Copy(ctx, st, ref, dst, ref, copyOpts)
 	if err != nil {
 		return fmt.Errorf("failed to push %s: %w", ref, err)
 	}
@@ -59,30 +104,59 @@ func Push(ctx context.Context, st *store.Store, ref string) error {
 }
 
 // Pull downloads a skill artifact from a remote registry to the local store.
-func Pull(ctx context.Context, st *store.Store, ref string) error {
-	repo, err := remote.NewRepository(ref)
+func Pull(ctx context.Context, st *store.Store, ref string, opts ...Option) error {
+	repo, err := newRepository(ref)
 	if err != nil {
-		return fmt.Errorf("invalid reference %s: %w", ref, err)
+		return err
 	}
 
-	baseTransport := otelhttp.NewTransport(http.DefaultTransport)
-	retryTransport := retry.NewTransport(baseTransport)
-	httpClient := &http.Client{
-		Transport: retryTransport,
-	}
-
-	repo.Client = &auth.Client{
-		Client:     httpClient,
-		Cache:      auth.DefaultCache,
-		Credential: credentials.Credential(skrauth.NewStore()),
+	// Blobs are copied before the manifest is tagged; keep them from being pruned meanwhile
+	unlock, err := st.RLock(ctx)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
 	// 2. Copy from Remote Repo to Local Store
-	// We copy the tagged reference.
-	_, err = oras.Copy(ctx, repo, ref, st, ref, oras.DefaultCopyOptions)
+	// We copy the tagged reference. References by digest (e.g. pinned skills) are tagged
+	// "repo:sha256-<hex>" rather than "repo@sha256:<hex>", so that they are listed and kept
+	// by prune like any other tag.
+	dstRef := ref
+	if repo.Reference.ValidateReferenceAsDigest() == nil {
+		dstRef = digestTag(ref)
+	}
+	copyOpts, dst := newOptions(opts).copyOptions(st)
+	_, err = oras.Copy(ctx, repo, ref, dst, dstRef, copyOpts)
 	if err != nil {
 		return fmt.Errorf("failed to pull %s: %w", ref, err)
 	}
 
 	return nil
 }
+
+// Restore fetches the blob desc of the artifact ref from its registry, to replace a copy in
+// the local store that is missing or corrupt.
+func Restore(ctx context.Context, st *store.Store, ref string, desc ocispec.Descriptor) error {
+	repo, err := newRepository(ref)
+	if err != nil {
+		return err
+	}
+
+	rc, err := repo.Fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w", desc.Digest, ref, err)
+	}
+	defer rc.Close()
+
+	return st.Restore(ctx, desc, rc)
+}
+
+// digestTag returns the tag the artifact ref ("repo@sha256:<hex>") is pulled into the local
+// store as: "repo:sha256-<hex>". Other references are returned as they are.
+func digestTag(ref string) string {
+	i := strings.LastIndex(ref, "@")
+	if i < 0 {
+		return ref
+	}
+	return ref[:i] + ":" + strings.Replace(ref[i+1:], ":", "-", 1)
+}
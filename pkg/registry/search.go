@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andrewhowdencom/skr/pkg/index"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Catalog lists the repositories of the registry at host. Not every registry supports
+// listing repositories (GHCR and Docker Hub, for example, do not).
+func Catalog(ctx context.Context, host string) ([]string, error) {
+	reg, err := remote.NewRegistry(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry %s: %w", host, err)
+	}
+	opts := optionsFor(host)
+	reg.PlainHTTP = opts.PlainHTTP
+	if reg.Client, err = newClient(opts); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+
+	var repos []string
+	err = reg.Repositories(ctx, "", func(batch []string) error {
+		repos = append(repos, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of %s: %w", host, err)
+	}
+	return repos, nil
+}
+
+// Index builds a search index of the registry at host, by listing its repositories and
+// reading the manifest of every tag. Repositories that cannot be read are skipped.
+func Index(ctx context.Context, host string) (*index.Index, error) {
+	repos, err := Catalog(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &index.Index{Version: index.Version, Registry: host}
+	for _, name := range repos {
+		repo, err := newRepository(host + "/" + name)
+		if err != nil {
+			continue
+		}
+
+		tags := make(map[string]ocispec.Descriptor)
+		err = repo.Tags(ctx, "", func(batch []string) error {
+			for _, tag := range batch {
+				desc, err := repo.Resolve(ctx, tag)
+				if err != nil {
+					return err
+				}
+				tags[tag] = desc
+			}
+			return nil
+		})
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+
+		s, err := index.NewSkill(ctx, repo, name, tags, index.Options{})
+		if err != nil {
+			continue
+		}
+		idx.Skills = append(idx.Skills, *s)
+	}
+	idx.Sort()
+	return idx, nil
+}
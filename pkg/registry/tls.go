@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// HostOptions configures the connection to a registry host.
+type HostOptions struct {
+	// PlainHTTP connects over HTTP instead of HTTPS, e.g. to a local registry:2.
+	PlainHTTP bool
+	// Insecure skips the verification of the registry's TLS certificate.
+	Insecure bool
+	// CAFile is a PEM file of certificate authorities to trust, in addition to the system's.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and its key, for mutual TLS.
+	CertFile string
+	KeyFile  string
+}
+
+var (
+	hostsMu      sync.RWMutex
+	defaultHost  HostOptions
+	hostsOptions map[string]HostOptions
+)
+
+// Configure sets the options of the connections to registries: hosts by host (e.g.
+// "localhost:5000"), and defaults for every other host.
+func Configure(defaults HostOptions, hosts map[string]HostOptions) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+	defaultHost, hostsOptions = defaults, hosts
+}
+
+// optionsFor returns the options of the connection to host.
+func optionsFor(host string) HostOptions {
+	hostsMu.RLock()
+	defer hostsMu.RUnlock()
+	if opts, ok := hostsOptions[host]; ok {
+		return opts
+	}
+	return defaultHost
+}
+
+// transport returns the base transport for connections with opts.
+func (opts HostOptions) transport() (http.RoundTripper, error) {
+	if !opts.Insecure && opts.CAFile == "" && opts.CertFile == "" && opts.KeyFile == "" {
+		return http.DefaultTransport, nil
+	}
+
+	config := &tls.Config{InsecureSkipVerify: opts.Insecure}
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return transport, nil
+}
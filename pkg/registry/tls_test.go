@@ -0,0 +1,53 @@
+package registry
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostOptionsTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	get := func(opts HostOptions) error {
+		transport, err := opts.transport()
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// The test server's certificate is not trusted by default
+	assert.Error(t, get(HostOptions{}))
+	assert.NoError(t, get(HostOptions{Insecure: true}))
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, ca, 0644))
+	assert.NoError(t, get(HostOptions{CAFile: caFile}))
+
+	_, err := HostOptions{CertFile: caFile}.transport()
+	assert.Error(t, err)
+}
+
+func TestConfigure(t *testing.T) {
+	Configure(HostOptions{}, map[string]HostOptions{"localhost:5000": {PlainHTTP: true}})
+	defer Configure(HostOptions{}, nil)
+
+	repo, err := newRepository("localhost:5000/skills/my-skill:v1")
+	require.NoError(t, err)
+	assert.True(t, repo.PlainHTTP)
+
+	repo, err = newRepository("ghcr.io/skills/my-skill:v1")
+	require.NoError(t, err)
+	assert.False(t, repo.PlainHTTP)
+}
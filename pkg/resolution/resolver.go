@@ -77,7 +77,7 @@ func (r *Resolver) Resolve(ctx context.Context, rootRef string) ([]string, error
 		}
 
 		// Parse Dependencies from Annotation
-		if depsJSON, ok := manifest.Annotations["com.skr.dependencies"]; ok {
+		if depsJSON, ok := manifest.Annotations[store.AnnotationDependencies]; ok {
 			var deps []string
 			if err := json.Unmarshal([]byte(depsJSON), &deps); err != nil {
 				return nil, fmt.Errorf("failed to parse dependencies for %s: %w", currentRef, err)
@@ -0,0 +1,67 @@
+package skill
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ScriptsDir is the conventional directory for executable tools within a skill.
+	ScriptsDir = "scripts"
+)
+
+// CheckLink verifies that a symlink stored at name (relative to the skill root)
+// and pointing at target is relative and stays inside the skill directory.
+func CheckLink(name, target string) error {
+	if target == "" {
+		return fmt.Errorf("symlink %s has an empty target", name)
+	}
+	if filepath.IsAbs(target) {
+		return fmt.Errorf("symlink %s points to absolute path %s", name, target)
+	}
+
+	resolved := filepath.Join(filepath.Dir(name), target)
+	if !filepath.IsLocal(resolved) {
+		return fmt.Errorf("symlink %s points outside the skill directory (%s)", name, target)
+	}
+	return nil
+}
+
+// NonExecutableScripts returns the regular files under the scripts/ directory of
+// the skill at dir that have no executable bit set.
+func NonExecutableScripts(dir string) ([]string, error) {
+	scriptsDir := filepath.Join(dir, ScriptsDir)
+	if _, err := os.Stat(scriptsDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var files []string
+	err := filepath.WalkDir(scriptsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().Perm()&0111 == 0 {
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(relPath))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", scriptsDir, err)
+	}
+
+	return files, nil
+}
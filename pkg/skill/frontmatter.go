@@ -0,0 +1,136 @@
+package skill
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// utf8BOM is the byte order mark some editors write at the start of UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseError is a problem with a SKILL.md, located by line and column (both 1-based).
+// Column is 0 if only the line is known.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Frontmatter is the YAML frontmatter of a SKILL.md, split from its markdown body.
+type Frontmatter struct {
+	// YAML is the frontmatter, without delimiters and with line endings normalized to \n.
+	YAML []byte
+	// Line is the line of the file the YAML starts on.
+	Line int
+	// Body is the markdown after the frontmatter, with line endings normalized to \n.
+	Body []byte
+	// BodyLine is the line of the file the body starts on.
+	BodyLine int
+}
+
+// ParseFrontmatter splits the content of a SKILL.md into its frontmatter and body.
+//
+// The frontmatter must start on the first line with "---", and ends at the next line that
+// is exactly "---" (or "..."). Delimiters may have trailing whitespace. A UTF-8 byte order
+// mark and CRLF line endings are accepted.
+func ParseFrontmatter(content []byte) (*Frontmatter, error) {
+	content = bytes.TrimPrefix(content, utf8BOM)
+	lines := bytes.SplitAfter(content, []byte("\n"))
+
+	if len(lines) == 0 || !isDelimiter(lines[0], false) {
+		return nil, &ParseError{Line: 1, Column: 1, Message: "missing frontmatter start delimiter '---'"}
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if isDelimiter(lines[i], true) {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return nil, &ParseError{Line: 1, Column: 1, Message: "missing frontmatter end delimiter '---'"}
+	}
+
+	return &Frontmatter{
+		YAML:     normalizeLines(lines[1:end]),
+		Line:     2,
+		Body:     normalizeLines(lines[end+1:]),
+		BodyLine: end + 2,
+	}, nil
+}
+
+// Node parses the frontmatter into a YAML node, with line numbers relative to the file.
+func (f *Frontmatter) Node() (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(f.YAML, &doc); err != nil {
+		return nil, f.yamlError(err)
+	}
+	offsetLines(&doc, f.Line-1)
+	return &doc, nil
+}
+
+// Decode unmarshals the frontmatter into v. Errors are reported as a *ParseError.
+func (f *Frontmatter) Decode(v any) error {
+	if err := yaml.Unmarshal(f.YAML, v); err != nil {
+		return f.yamlError(err)
+	}
+	return nil
+}
+
+// yamlErrorLine matches the line number in yaml.v3 error messages.
+var yamlErrorLine = regexp.MustCompile(`line (\d+): `)
+
+// yamlError converts a YAML error into a *ParseError on the corresponding line of the file.
+// Type errors may list several problems; the first one is used for the position.
+func (f *Frontmatter) yamlError(err error) error {
+	msg := err.Error()
+	m := yamlErrorLine.FindStringSubmatchIndex(msg)
+	if m == nil {
+		return &ParseError{Line: f.Line, Message: msg}
+	}
+
+	line, _ := strconv.Atoi(msg[m[2]:m[3]])
+	detail := msg[m[1]:]
+	if nl := bytes.IndexByte([]byte(detail), '\n'); nl != -1 {
+		detail = detail[:nl]
+	}
+	return &ParseError{Line: line + f.Line - 1, Message: detail}
+}
+
+func isDelimiter(line []byte, closing bool) bool {
+	line = bytes.TrimRight(line, " \t\r\n")
+	if string(line) == "---" {
+		return true
+	}
+	return closing && string(line) == "..."
+}
+
+func normalizeLines(lines [][]byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if bytes.HasSuffix(line, []byte("\r\n")) {
+			line = append(line[:len(line)-2:len(line)-2], '\n')
+		}
+		buf.Write(line)
+	}
+	return buf.Bytes()
+}
+
+func offsetLines(node *yaml.Node, offset int) {
+	node.Line += offset
+	for _, child := range node.Content {
+		offsetLines(child, offset)
+	}
+}
@@ -0,0 +1,83 @@
+package skill
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFrontmatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		yaml     string
+		body     string
+		bodyLine int
+	}{
+		{
+			name:     "LF",
+			content:  "---\nname: a\n---\n# Body\n",
+			yaml:     "name: a\n",
+			body:     "# Body\n",
+			bodyLine: 4,
+		},
+		{
+			name:     "CRLF and BOM",
+			content:  "\xef\xbb\xbf---\r\nname: a\r\ndescription: b\r\n---\r\n# Body\r\n",
+			yaml:     "name: a\ndescription: b\n",
+			body:     "# Body\n",
+			bodyLine: 5,
+		},
+		{
+			name:     "trailing whitespace on delimiters",
+			content:  "--- \nname: a\n---\t\n\nbody\n",
+			yaml:     "name: a\n",
+			body:     "\nbody\n",
+			bodyLine: 4,
+		},
+		{
+			name:     "horizontal rules",
+			content:  "---\nname: a\nnote: ----\n...\nabove\n---\nbelow\n",
+			yaml:     "name: a\nnote: ----\n",
+			body:     "above\n---\nbelow\n",
+			bodyLine: 5,
+		},
+		{
+			name:     "no body",
+			content:  "---\nname: a\n---",
+			yaml:     "name: a\n",
+			body:     "",
+			bodyLine: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, err := ParseFrontmatter([]byte(tt.content))
+			require.NoError(t, err)
+			assert.Equal(t, tt.yaml, string(fm.YAML))
+			assert.Equal(t, tt.body, string(fm.Body))
+			assert.Equal(t, tt.bodyLine, fm.BodyLine)
+		})
+	}
+}
+
+func TestParseFrontmatter_Errors(t *testing.T) {
+	var perr *ParseError
+
+	_, err := ParseFrontmatter([]byte("# No frontmatter\n"))
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, 1, perr.Line)
+
+	_, err = ParseFrontmatter([]byte("---\nname: a\n----\n"))
+	require.True(t, errors.As(err, &perr))
+	assert.Contains(t, perr.Message, "end delimiter")
+
+	// YAML errors are reported on the line of the file
+	_, err = parseFrontmatter([]byte("---\nname: a\ndescription: b\nversion: : 1\n---\n"))
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, 4, perr.Line)
+	assert.Equal(t, "line 4: mapping values are not allowed in this context", err.Error())
+}
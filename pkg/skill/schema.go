@@ -0,0 +1,235 @@
+package skill
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaID identifies the frontmatter schema.
+const SchemaID = "https://andrewhowdencom.github.io/skr/schemas/skill-frontmatter.json"
+
+// Schema is a JSON Schema (draft 2020-12), limited to the keywords skr generates.
+type Schema struct {
+	SchemaURI            string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+}
+
+// SchemaError is a violation of the schema, located in the YAML document.
+type SchemaError struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// FrontmatterSchema returns the JSON Schema of the SKILL.md frontmatter. It is generated
+// from the Skill type: property names come from the yaml tags, descriptions from the desc
+// tags, and constraints from the schema tags (required, minLength, maxLength and pattern).
+// Inline maps allow additional properties.
+func FrontmatterSchema() *Schema {
+	s := schemaFor(reflect.TypeOf(Skill{}))
+	s.SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+	s.ID = SchemaID
+	s.Title = "Agent Skill frontmatter"
+	s.Description = "The YAML frontmatter of a " + SkillFileName + " file."
+	return s
+}
+
+func schemaFor(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		// Any value
+		return &Schema{}
+	}
+}
+
+func structSchema(t reflect.Type) *Schema {
+	closed := false
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: &closed}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			open := true
+			s.AdditionalProperties = &open
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		prop := schemaFor(f.Type)
+		prop.Description = f.Tag.Get("desc")
+		for _, constraint := range strings.Split(f.Tag.Get("schema"), ",") {
+			key, value, _ := strings.Cut(constraint, "=")
+			switch key {
+			case "required":
+				s.Required = append(s.Required, name)
+			case "minLength":
+				n, _ := strconv.Atoi(value)
+				prop.MinLength = &n
+			case "maxLength":
+				n, _ := strconv.Atoi(value)
+				prop.MaxLength = &n
+			case "pattern":
+				prop.Pattern = value
+			}
+		}
+		s.Properties[name] = prop
+	}
+
+	return s
+}
+
+// ValidateNode validates a YAML node against the schema.
+func (s *Schema) ValidateNode(node *yaml.Node) []SchemaError {
+	errs := s.validate(node, "")
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	return errs
+}
+
+func (s *Schema) validate(node *yaml.Node, path string) []SchemaError {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	fail := func(format string, args ...any) []SchemaError {
+		return []SchemaError{{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	switch s.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			return fail("expected an object")
+		}
+		var errs []SchemaError
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			seen[key.Value] = true
+			prop, ok := s.Properties[key.Value]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					errs = append(errs, SchemaError{Line: key.Line, Column: key.Column, Path: path, Message: fmt.Sprintf("unknown property %q", key.Value)})
+				}
+				continue
+			}
+			errs = append(errs, prop.validate(value, joinSchemaPath(path, key.Value))...)
+		}
+		for _, name := range s.Required {
+			if !seen[name] {
+				errs = append(errs, fail("missing required property %q", name)...)
+			}
+		}
+		return errs
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			return fail("expected an array")
+		}
+		var errs []SchemaError
+		for i, item := range node.Content {
+			if s.Items != nil {
+				errs = append(errs, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+		return errs
+	case "string":
+		// Unquoted numbers and booleans (e.g. version: 1.0) load as strings, so only reject
+		// nulls and collections
+		if node.Kind != yaml.ScalarNode || node.ShortTag() == "!!null" {
+			return fail("expected a string")
+		}
+		length := utf8.RuneCountInString(node.Value)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fail("must be at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err == nil && !re.MatchString(node.Value) {
+				return fail("must match %s", s.Pattern)
+			}
+		}
+	case "boolean", "integer", "number":
+		tags := map[string]string{"boolean": "!!bool", "integer": "!!int", "number": "!!float"}
+		if node.Kind != yaml.ScalarNode || (node.ShortTag() != tags[s.Type] && !(s.Type == "number" && node.ShortTag() == "!!int")) {
+			return fail("expected a %s", s.Type)
+		}
+	}
+	return nil
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// ValidateFrontmatter validates the frontmatter of a SKILL.md against FrontmatterSchema.
+// Errors are located by their line in content. Content that cannot be parsed at all is
+// reported as a *ParseError.
+func ValidateFrontmatter(content []byte) ([]SchemaError, error) {
+	fm, err := ParseFrontmatter(content)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := fm.Node()
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return []SchemaError{{Line: fm.Line, Message: "frontmatter is empty"}}, nil
+	}
+
+	return FrontmatterSchema().ValidateNode(doc), nil
+}
@@ -0,0 +1,99 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionFields(t *testing.T) {
+	dir := t.TempDir()
+	content := `---
+name: extended
+description: A skill with extension fields.
+license: Apache-2.0
+homepage: https://example.com
+tags: [go, testing]
+metadata:
+  author: someone
+  version: "1.0"
+  team: platform
+---
+# Extended
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SkillFileName), []byte(content), 0644))
+
+	s, err := Load(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Apache-2.0", s.License)
+	assert.Equal(t, "someone", s.Metadata.Author)
+	assert.Equal(t, map[string]any{"team": "platform"}, s.Metadata.Extra)
+	assert.Equal(t, map[string]any{
+		"license":  "Apache-2.0",
+		"homepage": "https://example.com",
+		"tags":     []any{"go", "testing"},
+		"metadata": map[string]any{"team": "platform"},
+	}, s.ExtensionFields())
+}
+
+func TestValidateFrontmatter(t *testing.T) {
+	content := []byte(`---
+name: Not_Valid
+description: [42]
+dependencies: ghcr.io/example/dep:v1
+homepage: https://example.com
+---
+`)
+	errs, err := ValidateFrontmatter(content)
+	require.NoError(t, err)
+	require.Len(t, errs, 3)
+
+	assert.Equal(t, 2, errs[0].Line)
+	assert.Contains(t, errs[0].Error(), "name: must match")
+	assert.Equal(t, 3, errs[1].Line)
+	assert.Contains(t, errs[1].Error(), "description: expected a string")
+	assert.Equal(t, 4, errs[2].Line)
+	assert.Contains(t, errs[2].Error(), "dependencies: expected an array")
+
+	errs, err = ValidateFrontmatter([]byte("---\nname: valid\n---\n"))
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `missing required property "description"`)
+}
+
+func TestValidateFrontmatter_UnquotedScalars(t *testing.T) {
+	content := []byte(`---
+name: versioned
+description: true
+metadata:
+  version: 1.0
+  build: 42
+---
+`)
+	errs, err := ValidateFrontmatter(content)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = ValidateFrontmatter([]byte("---\nname: versioned\ndescription: ~\n---\n"))
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "description: expected a string")
+}
+
+func TestFrontmatterSchema_Published(t *testing.T) {
+	// The schema published with the documentation must match the Go types
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	require.NoError(t, enc.Encode(FrontmatterSchema()))
+
+	published, err := os.ReadFile(filepath.Join("..", "..", "docs", "schemas", "skill-frontmatter.json"))
+	require.NoError(t, err)
+	assert.Equal(t, buf.String(), string(published), "regenerate with: skr schema > docs/schemas/skill-frontmatter.json")
+}
@@ -1,25 +1,37 @@
 package skill
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
-
-	"gopkg.in/yaml.v3"
 )
 
 // Skill represents the metadata and structure of an Agent Skill
 type Skill struct {
-	Name         string   `yaml:"name"`
-	Description  string   `yaml:"description"`
-	Dependencies []string `yaml:"dependencies,omitempty"`
-	Metadata     struct {
-		Author  string `yaml:"author,omitempty"`
-		Version string `yaml:"version,omitempty"`
-	} `yaml:"metadata,omitempty"`
-	Path string `yaml:"-"` // Local path to the skill directory
+	Name         string   `yaml:"name" json:"name" desc:"Name of the skill. Should match the name of the skill directory." schema:"required,minLength=1,maxLength=64,pattern=^[a-z0-9-]+$"`
+	Description  string   `yaml:"description" json:"description" desc:"What the skill does, and when an agent should use it." schema:"required,minLength=1,maxLength=1024"`
+	Dependencies []string `yaml:"dependencies,omitempty" json:"dependencies,omitempty" desc:"OCI references of skills this skill depends on."`
+	// License, Compatibility and AllowedTools are optional fields defined by the Agent Skills specification.
+	License       string   `yaml:"license,omitempty" json:"license,omitempty" desc:"License of the skill, as an SPDX expression or a reference to a bundled license file."`
+	Compatibility string   `yaml:"compatibility,omitempty" json:"compatibility,omitempty" desc:"Environment requirements of the skill (e.g. products, system packages, network access)." schema:"maxLength=500"`
+	AllowedTools  string   `yaml:"allowed-tools,omitempty" json:"allowed-tools,omitempty" desc:"Space-delimited list of tools the skill may use without asking."`
+	Metadata      Metadata `yaml:"metadata,omitempty" json:"metadata,omitempty" desc:"Additional properties of the skill."`
+	// Extensions holds any other frontmatter keys (e.g. tags or homepage), so that they are not lost.
+	Extensions map[string]any `yaml:",inline" json:"-"`
+
+	Path string `yaml:"-" json:"-"` // Local path to the skill directory
+	// Body is the markdown following the frontmatter, and BodyLine the line of SKILL.md it starts on.
+	Body     string `yaml:"-" json:"-"`
+	BodyLine int    `yaml:"-" json:"-"`
+}
+
+// Metadata holds the well-known metadata properties, and any others in Extra.
+type Metadata struct {
+	Author  string `yaml:"author,omitempty" json:"author,omitempty" desc:"Author of the skill."`
+	Version string `yaml:"version,omitempty" json:"version,omitempty" desc:"Version of the skill."`
+	// Extra holds any other metadata properties.
+	Extra map[string]any `yaml:",inline" json:"-"`
 }
 
 const (
@@ -93,6 +105,10 @@ func (s *Skill) Validate() error {
 		return fmt.Errorf("description must be 1024 characters or less")
 	}
 
+	if len(s.Compatibility) > 500 {
+		return fmt.Errorf("compatibility must be 500 characters or less")
+	}
+
 	// Validate directory structure matches name (warning or error?)
 	// Strictly speaking, the spec says name "Should match the directory name".
 	// We won't enforce it as a hard error here but it's good practice.
@@ -100,24 +116,45 @@ func (s *Skill) Validate() error {
 	return nil
 }
 
-func parseFrontmatter(content []byte) (*Skill, error) {
-	// Frontmatter is anticipated to be between the first two "---" lines
-	if !bytes.HasPrefix(content, []byte("---\n")) {
-		return nil, fmt.Errorf("missing frontmatter start delimiter '---'")
+// ExtensionFields returns the frontmatter fields that skr does not interpret itself: the
+// optional specification fields, extension keys, and metadata properties other than author
+// and version (under "metadata"). It returns nil if there are none.
+func (s *Skill) ExtensionFields() map[string]any {
+	fields := make(map[string]any)
+	for k, v := range s.Extensions {
+		fields[k] = v
+	}
+	if s.License != "" {
+		fields["license"] = s.License
+	}
+	if s.Compatibility != "" {
+		fields["compatibility"] = s.Compatibility
+	}
+	if s.AllowedTools != "" {
+		fields["allowed-tools"] = s.AllowedTools
+	}
+	if len(s.Metadata.Extra) > 0 {
+		fields["metadata"] = s.Metadata.Extra
 	}
 
-	end := bytes.Index(content[4:], []byte("\n---"))
-	if end == -1 {
-		return nil, fmt.Errorf("missing frontmatter end delimiter '---'")
+	if len(fields) == 0 {
+		return nil
 	}
+	return fields
+}
 
-	// Adjust index to account for skipping the first 4 bytes
-	frontmatter := content[4 : 4+end]
+func parseFrontmatter(content []byte) (*Skill, error) {
+	fm, err := ParseFrontmatter(content)
+	if err != nil {
+		return nil, err
+	}
 
 	var s Skill
-	if err := yaml.Unmarshal(frontmatter, &s); err != nil {
+	if err := fm.Decode(&s); err != nil {
 		return nil, err
 	}
+	s.Body = string(fm.Body)
+	s.BodyLine = fm.BodyLine
 
 	return &s, nil
 }
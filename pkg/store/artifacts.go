@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// Artifact is a skill in the store, as listed by Artifacts.
+type Artifact struct {
+	// Ref is the tag of the artifact, or empty if it is dangling.
+	Ref        string
+	Descriptor ocispec.Descriptor
+	// Name is the name of the skill.
+	Name string
+	// Created is when the artifact was built, or zero if it is unknown.
+	Created time.Time
+	// Size is the size of the manifest, config and layers.
+	Size int64
+	// Dangling reports whether the artifact has no tag, and cannot be reached from one: it
+	// is removed by Prune.
+	Dangling bool
+	// Err is set if the manifest could not be read. Only Ref and Descriptor are set then.
+	Err error
+}
+
+// Artifacts lists every tag in the store, sorted by ref, followed by the skills that are
+// no longer tagged (see Artifact.Dangling). A skill with several tags is listed once per tag.
+func (s *Store) Artifacts(ctx context.Context) ([]Artifact, error) {
+	o, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := s.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reachable, err := reachableBlobs(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts, dangling []Artifact
+	for _, desc := range idx.Manifests {
+		ref := desc.Annotations[ocispec.AnnotationRefName]
+		switch {
+		case ref != "":
+			artifacts = append(artifacts, Artifact{Ref: ref, Descriptor: plainDescriptor(desc)})
+		case !reachable[desc.Digest] && isSkill(ctx, o, desc):
+			dangling = append(dangling, Artifact{Descriptor: plainDescriptor(desc), Dangling: true})
+		}
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Ref < artifacts[j].Ref })
+	sort.Slice(dangling, func(i, j int) bool { return dangling[i].Descriptor.Digest < dangling[j].Descriptor.Digest })
+	artifacts = append(artifacts, dangling...)
+
+	for i := range artifacts {
+		a := &artifacts[i]
+		if a.Err = describeArtifact(ctx, o, a); a.Err != nil {
+			a.Err = fmt.Errorf("failed to read %s: %w", a.Descriptor.Digest, a.Err)
+		}
+	}
+	return artifacts, nil
+}
+
+// describeArtifact sets the name, creation time and size of a from its manifest.
+func describeArtifact(ctx context.Context, fetcher content.Fetcher, a *Artifact) error {
+	data, err := content.FetchAll(ctx, fetcher, a.Descriptor)
+	if err != nil {
+		return err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+
+	a.Name = manifest.Annotations[ocispec.AnnotationTitle]
+	if created := manifest.Annotations[ocispec.AnnotationCreated]; created != "" {
+		if t, err := time.Parse(time.RFC3339, created); err == nil {
+			a.Created = t
+		}
+	}
+	a.Size = a.Descriptor.Size + manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		a.Size += layer.Size
+	}
+	return nil
+}
+
+// isSkill reports whether desc is the manifest of a skill. Entries of index.json often don't
+// have an artifactType (e.g. after a pull, or for skills built by older versions), so the
+// manifest is read if needed.
+func isSkill(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) bool {
+	if desc.ArtifactType == ArtifactType {
+		return true
+	}
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		return false
+	}
+	data, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return false
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return false
+	}
+	return manifest.ArtifactType == ArtifactType || manifest.Config.MediaType == MediaTypeSkillConfig
+}
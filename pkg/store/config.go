@@ -0,0 +1,189 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/andrewhowdencom/skr/pkg/skill"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// AnnotationDependencies records the dependencies of a skill on its manifest, as a JSON array
+// of references.
+const AnnotationDependencies = "com.skr.dependencies"
+
+// Annotations used by older versions of skr, where OCI now defines standard keys. They are
+// still read (see Annotation), but no longer written.
+const (
+	LegacyAnnotationDescription = "com.skr.description"
+	LegacyAnnotationAuthor      = "com.skr.author"
+	LegacyAnnotationVersion     = "com.skr.version"
+)
+
+var legacyAnnotations = map[string]string{
+	ocispec.AnnotationDescription: LegacyAnnotationDescription,
+	ocispec.AnnotationAuthors:     LegacyAnnotationAuthor,
+	ocispec.AnnotationVersion:     LegacyAnnotationVersion,
+}
+
+// Annotation returns the value of the standard annotation key, falling back to the legacy
+// key that older versions of skr used in its place.
+func Annotation(annotations map[string]string, key string) string {
+	if v, ok := annotations[key]; ok {
+		return v
+	}
+	if legacy, ok := legacyAnnotations[key]; ok {
+		return annotations[legacy]
+	}
+	return ""
+}
+
+// SkillConfig is the content of the config blob (MediaTypeSkillConfig) of a skill artifact.
+// It carries everything known about the skill at build time, so that consumers do not need
+// to unpack the layer.
+type SkillConfig struct {
+	// Created is when the artifact was built.
+	Created time.Time `json:"created"`
+	// Name and Description are copied from the frontmatter for convenience.
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Frontmatter is the complete frontmatter of SKILL.md, including extension fields.
+	Frontmatter map[string]any `json:"frontmatter"`
+	// Files lists every entry in the skill layer.
+	Files []FileInfo `json:"files"`
+	// Build describes how the artifact was built.
+	Build BuildInfo `json:"build"`
+}
+
+// FileInfo describes a single entry in the skill layer.
+type FileInfo struct {
+	// Path is relative to the skill directory, with forward slashes.
+	Path string `json:"path"`
+	// Type is "file", "dir" or "symlink".
+	Type string `json:"type"`
+	// Mode is the permission bits, in octal (e.g. "0755").
+	Mode string `json:"mode"`
+	Size int64  `json:"size,omitempty"`
+	// Digest is the digest of a file's content.
+	Digest string `json:"digest,omitempty"`
+	// Target is the target of a symlink.
+	Target string `json:"target,omitempty"`
+}
+
+// BuildInfo describes the tool and source an artifact was built from.
+type BuildInfo struct {
+	Tool        string `json:"tool"`
+	ToolVersion string `json:"toolVersion,omitempty"`
+	// Source and Revision identify the source repository, if known.
+	Source   string `json:"source,omitempty"`
+	Revision string `json:"revision,omitempty"`
+}
+
+// newSkillConfig builds the config for the skill s.
+func newSkillConfig(s *skill.Skill, frontmatter map[string]any, files []FileInfo, created time.Time) SkillConfig {
+	build := BuildInfo{Tool: "skr"}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		build.ToolVersion = info.Main.Version
+	}
+
+	if frontmatter == nil {
+		frontmatter = map[string]any{}
+	}
+	if files == nil {
+		files = []FileInfo{}
+	}
+
+	return SkillConfig{
+		Created:     created,
+		Name:        s.Name,
+		Description: s.Description,
+		Frontmatter: frontmatter,
+		Files:       files,
+		Build:       build,
+	}
+}
+
+// skillAnnotations returns the manifest annotations describing s, using the standard OCI
+// keys where there are any.
+func skillAnnotations(s *skill.Skill) (map[string]string, error) {
+	annotations := map[string]string{
+		ocispec.AnnotationTitle: s.Name,
+	}
+	if s.Description != "" {
+		annotations[ocispec.AnnotationDescription] = s.Description
+	}
+	if s.Metadata.Author != "" {
+		annotations[ocispec.AnnotationAuthors] = s.Metadata.Author
+	}
+	if s.Metadata.Version != "" {
+		annotations[ocispec.AnnotationVersion] = s.Metadata.Version
+	}
+	if s.License != "" {
+		annotations[ocispec.AnnotationLicenses] = s.License
+	}
+	if len(s.Dependencies) > 0 {
+		depsJSON, err := json.Marshal(s.Dependencies)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal dependencies: %w", err)
+		}
+		annotations[AnnotationDependencies] = string(depsJSON)
+	}
+	if extensions := s.ExtensionFields(); extensions != nil {
+		extensionsJSON, err := json.Marshal(extensions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal extension fields: %w", err)
+		}
+		annotations[AnnotationExtensions] = string(extensionsJSON)
+	}
+	return annotations, nil
+}
+
+// readFrontmatter reads the complete frontmatter of the SKILL.md in dir.
+func readFrontmatter(dir string) (map[string]any, error) {
+	content, err := os.ReadFile(filepath.Join(dir, skill.SkillFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", skill.SkillFileName, err)
+	}
+
+	fm, err := skill.ParseFrontmatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", skill.SkillFileName, err)
+	}
+
+	var frontmatter map[string]any
+	if err := fm.Decode(&frontmatter); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", skill.SkillFileName, err)
+	}
+	return frontmatter, nil
+}
+
+// SkillConfig fetches and decodes the config blob of manifest. Artifacts built by older
+// versions of skr only record the creation time.
+func (s *Store) SkillConfig(ctx context.Context, manifest ocispec.Manifest) (*SkillConfig, error) {
+	if manifest.Config.MediaType != MediaTypeSkillConfig {
+		return nil, fmt.Errorf("unexpected config media type %s", manifest.Config.MediaType)
+	}
+
+	rc, err := s.Fetch(ctx, manifest.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg SkillConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild_Config(t *testing.T) {
+	ctx := context.Background()
+
+	srcDir := filepath.Join(t.TempDir(), "config-skill")
+	content := `---
+name: config-skill
+description: test skill
+license: MIT
+dependencies:
+  - example.com/dep:v1
+metadata:
+  author: someone
+  version: 1.0.0
+homepage: https://example.com
+---
+# Config
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "scripts", "run.sh"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.Symlink("scripts/run.sh", filepath.Join(srcDir, "run")))
+
+	st, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	ref := "example.com/config-skill:v1"
+	require.NoError(t, st.Build(ctx, srcDir, ref, map[string]string{
+		ocispec.AnnotationSource: "https://example.com/repo",
+	}))
+
+	desc, err := st.Resolve(ctx, ref)
+	require.NoError(t, err)
+	rc, err := st.Fetch(ctx, desc)
+	require.NoError(t, err)
+	manifestBytes, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(manifestBytes, &manifest))
+
+	// The manifest is annotated from the skill, regardless of the caller
+	assert.Equal(t, ArtifactType, manifest.ArtifactType)
+	assert.Equal(t, "config-skill", manifest.Annotations[ocispec.AnnotationTitle])
+	assert.Equal(t, "test skill", manifest.Annotations[ocispec.AnnotationDescription])
+	assert.Equal(t, "someone", manifest.Annotations[ocispec.AnnotationAuthors])
+	assert.Equal(t, "1.0.0", manifest.Annotations[ocispec.AnnotationVersion])
+	assert.Equal(t, "MIT", manifest.Annotations[ocispec.AnnotationLicenses])
+	assert.JSONEq(t, `["example.com/dep:v1"]`, manifest.Annotations[AnnotationDependencies])
+	assert.JSONEq(t, `{"homepage":"https://example.com","license":"MIT"}`, manifest.Annotations[AnnotationExtensions])
+	assert.NotEmpty(t, manifest.Annotations[ocispec.AnnotationCreated])
+
+	cfg, err := st.SkillConfig(ctx, manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "config-skill", cfg.Name)
+	assert.Equal(t, "test skill", cfg.Description)
+	assert.Equal(t, "https://example.com", cfg.Frontmatter["homepage"])
+	assert.Equal(t, map[string]any{"author": "someone", "version": "1.0.0"}, cfg.Frontmatter["metadata"])
+	assert.Equal(t, "skr", cfg.Build.Tool)
+	assert.Equal(t, "https://example.com/repo", cfg.Build.Source)
+	assert.False(t, cfg.Created.IsZero())
+
+	files := make(map[string]FileInfo)
+	for _, f := range cfg.Files {
+		files[f.Path] = f
+	}
+	assert.Equal(t, "file", files["SKILL.md"].Type)
+	assert.Equal(t, int64(len(content)), files["SKILL.md"].Size)
+	assert.NotEmpty(t, files["SKILL.md"].Digest)
+	assert.Equal(t, FileInfo{Path: "scripts", Type: "dir", Mode: "0755"}, files["scripts"])
+	assert.Equal(t, "0755", files["scripts/run.sh"].Mode)
+	assert.Equal(t, "symlink", files["run"].Type)
+	assert.Equal(t, "scripts/run.sh", files["run"].Target)
+
+	// The layer lists the same files
+	require.Len(t, manifest.Layers, 1)
+	layer, err := st.Fetch(ctx, manifest.Layers[0])
+	require.NoError(t, err)
+	defer layer.Close()
+	layerFiles, err := ReadLayerFiles(layer)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, cfg.Files, layerFiles)
+}
+
+func TestAnnotation(t *testing.T) {
+	legacy := map[string]string{LegacyAnnotationDescription: "old", LegacyAnnotationAuthor: "someone"}
+	assert.Equal(t, "old", Annotation(legacy, ocispec.AnnotationDescription))
+	assert.Equal(t, "someone", Annotation(legacy, ocispec.AnnotationAuthors))
+	assert.Equal(t, "", Annotation(legacy, ocispec.AnnotationVersion))
+
+	// The standard key takes precedence
+	both := map[string]string{ocispec.AnnotationDescription: "new", LegacyAnnotationDescription: "old"}
+	assert.Equal(t, "new", Annotation(both, ocispec.AnnotationDescription))
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// DiskUsage is the space used by the store, as reported by Usage.
+type DiskUsage struct {
+	// Blobs and Size count every blob in the store.
+	Blobs int
+	Size  int64
+	// Reclaimable and ReclaimableSize count the blobs that cannot be reached from a tag,
+	// which Prune removes.
+	Reclaimable     int
+	ReclaimableSize int64
+	// Tags is the usage of every tag, sorted by ref.
+	Tags []Usage
+	// Repositories is the usage of every repository (the tags of a repository taken
+	// together), sorted by name.
+	Repositories []Usage
+}
+
+// Usage is the space used by the blobs that can be reached from a tag or repository.
+type Usage struct {
+	// Name is the tag or the repository.
+	Name  string
+	Blobs int
+	Size  int64
+	// Shared is the part of Size used by blobs that other tags or other repositories reach
+	// as well.
+	Shared int64
+}
+
+// Unique is the part of Size used by blobs that only this tag or repository reaches, which
+// would be reclaimed if it was removed.
+func (u Usage) Unique() int64 {
+	return u.Size - u.Shared
+}
+
+// Usage reports the space used by the store. Reachability is walked like Prune does, once
+// for every tag.
+func (s *Store) Usage(ctx context.Context) (*DiskUsage, error) {
+	unlock, err := s.RLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	o, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := s.blobSizes()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if err := o.Tags(ctx, "", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	// The blobs of every tag, and how many tags and which repositories reach each blob
+	blobsOf := make(map[string]map[digest.Digest]bool, len(tags))
+	tagCount := make(map[digest.Digest]int)
+	reposOf := make(map[digest.Digest]map[string]bool)
+	walked := make(map[digest.Digest]map[digest.Digest]bool)
+	for _, tag := range tags {
+		desc, err := o.Resolve(ctx, tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", tag, err)
+		}
+		blobs, ok := walked[desc.Digest]
+		if !ok {
+			if blobs, err = reachableFrom(ctx, o, []ocispec.Descriptor{desc}); err != nil {
+				return nil, err
+			}
+			walked[desc.Digest] = blobs
+		}
+		blobsOf[tag] = blobs
+
+		repo := repositoryOf(tag)
+		for d := range blobs {
+			if reposOf[d] == nil {
+				reposOf[d] = make(map[string]bool)
+			}
+			tagCount[d]++
+			reposOf[d][repo] = true
+		}
+	}
+
+	usage := &DiskUsage{Blobs: len(sizes)}
+	for d, size := range sizes {
+		usage.Size += size
+		if tagCount[d] == 0 {
+			usage.Reclaimable++
+			usage.ReclaimableSize += size
+		}
+	}
+
+	repoBlobs := make(map[string]map[digest.Digest]bool)
+	for _, tag := range tags {
+		u := Usage{Name: tag}
+		repo := repositoryOf(tag)
+		if repoBlobs[repo] == nil {
+			repoBlobs[repo] = make(map[digest.Digest]bool)
+		}
+		for d := range blobsOf[tag] {
+			repoBlobs[repo][d] = true
+			size, ok := sizes[d]
+			if !ok {
+				continue // Missing, see Fsck
+			}
+			u.Blobs++
+			u.Size += size
+			if tagCount[d] > 1 {
+				u.Shared += size
+			}
+		}
+		usage.Tags = append(usage.Tags, u)
+	}
+	for repo, blobs := range repoBlobs {
+		u := Usage{Name: repo}
+		for d := range blobs {
+			size, ok := sizes[d]
+			if !ok {
+				continue
+			}
+			u.Blobs++
+			u.Size += size
+			if len(reposOf[d]) > 1 {
+				u.Shared += size
+			}
+		}
+		usage.Repositories = append(usage.Repositories, u)
+	}
+
+	sort.Slice(usage.Tags, func(i, j int) bool { return usage.Tags[i].Name < usage.Tags[j].Name })
+	sort.Slice(usage.Repositories, func(i, j int) bool { return usage.Repositories[i].Name < usage.Repositories[j].Name })
+	return usage, nil
+}
+
+// blobSizes returns the size of every blob in the store.
+func (s *Store) blobSizes() (map[digest.Digest]int64, error) {
+	sizes := make(map[digest.Digest]int64)
+
+	blobsDir := filepath.Join(s.path, ocispec.ImageBlobsDir)
+	algs, err := os.ReadDir(blobsDir)
+	if os.IsNotExist(err) {
+		return sizes, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blobs directory: %w", err)
+	}
+	for _, alg := range algs {
+		if !alg.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(blobsDir, alg.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blobs directory: %w", err)
+		}
+		for _, entry := range entries {
+			d := digest.NewDigestFromEncoded(digest.Algorithm(alg.Name()), entry.Name())
+			if entry.IsDir() || d.Validate() != nil {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat blob %s: %w", d, err)
+			}
+			sizes[d] = info.Size()
+		}
+	}
+	return sizes, nil
+}
+
+// repositoryOf strips the tag from ref.
+func repositoryOf(ref string) string {
+	if idx := strings.LastIndex(ref, ":"); idx != -1 && !strings.Contains(ref[idx:], "/") {
+		return ref[:idx]
+	}
+	return ref
+}
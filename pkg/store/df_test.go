@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	build := func(name, ref string) {
+		srcDir := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: "+name+"\n---\n"), 0644))
+		require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+	}
+	build("a", "localhost/a:v1")
+	desc, err := st.Resolve(ctx, "localhost/a:v1")
+	require.NoError(t, err)
+	require.NoError(t, st.Tag(ctx, desc, "localhost/a:latest"))
+	build("b", "localhost/b:v1")
+	build("garbage", "")
+
+	usage, err := st.Usage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 9, usage.Blobs)
+
+	count, size, err := st.Prune(ctx, PruneOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, usage.Reclaimable)
+	assert.Equal(t, count, usage.Reclaimable)
+	assert.Equal(t, size, usage.ReclaimableSize)
+
+	require.Len(t, usage.Tags, 3)
+	assert.Equal(t, "localhost/a:latest", usage.Tags[0].Name)
+	assert.Equal(t, "localhost/a:v1", usage.Tags[1].Name)
+	assert.Equal(t, "localhost/b:v1", usage.Tags[2].Name)
+	for _, u := range usage.Tags {
+		assert.Equal(t, 3, u.Blobs)
+	}
+	// Both tags of a share everything, so removing one of them reclaims nothing
+	assert.Equal(t, usage.Tags[0].Size, usage.Tags[0].Shared)
+	assert.Zero(t, usage.Tags[1].Unique())
+	assert.Zero(t, usage.Tags[2].Shared)
+
+	require.Len(t, usage.Repositories, 2)
+	assert.Equal(t, "localhost/a", usage.Repositories[0].Name)
+	assert.Equal(t, 3, usage.Repositories[0].Blobs)
+	assert.Equal(t, usage.Tags[1].Size, usage.Repositories[0].Unique())
+	assert.Equal(t, usage.Size, usage.Repositories[0].Size+usage.Repositories[1].Size+usage.ReclaimableSize)
+}
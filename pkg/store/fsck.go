@@ -0,0 +1,266 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+// Kinds of Problem found by Fsck.
+const (
+	// ProblemCorrupt is a blob whose content does not match its digest.
+	ProblemCorrupt = "corrupt"
+	// ProblemMissing is a blob that a tagged manifest refers to, but that is not in the store.
+	ProblemMissing = "missing"
+	// ProblemDangling is a tag whose manifest is not in the store.
+	ProblemDangling = "dangling"
+	// ProblemOrphan is a blob that cannot be reached from any tag. It is harmless, and is
+	// removed by Prune.
+	ProblemOrphan = "orphan"
+)
+
+// Problem is an inconsistency in the store.
+type Problem struct {
+	Kind       string
+	Descriptor ocispec.Descriptor
+	// Refs are the tags that the blob belongs to, for every kind but ProblemOrphan.
+	Refs []string
+}
+
+// FsckReport is the result of Fsck.
+type FsckReport struct {
+	// Blobs is the number of blobs that were checked.
+	Blobs    int
+	Problems []Problem
+}
+
+// Fsck checks the integrity of the store: every blob is re-hashed against its digest, and
+// every tag is walked (like Prune does) to find the blobs that are missing or corrupt.
+// Blobs that cannot be reached from any tag are reported as orphans.
+//
+// The store is read directly from disk, so Fsck works even if the index cannot be loaded
+// (see Open).
+func (s *Store) Fsck(ctx context.Context) (*FsckReport, error) {
+	unlock, err := s.RLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	idx, err := s.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := s.hashBlobs()
+	if err != nil {
+		return nil, err
+	}
+	report := &FsckReport{Blobs: len(blobs)}
+
+	storage, err := oci.NewStorage(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	// Only intact blobs are read
+	fetch := func(desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		if !blobs[desc.Digest].ok {
+			return nil, nil
+		}
+		return content.Successors(ctx, storage, desc)
+	}
+
+	// Referrers are listed in the index by digest, like every other manifest
+	referrers := make(map[digest.Digest][]ocispec.Descriptor)
+	for _, desc := range idx.Manifests {
+		if !blobs[desc.Digest].ok {
+			continue
+		}
+		subject, err := subjectOf(ctx, storage, desc)
+		if err != nil {
+			return nil, err
+		}
+		if subject != nil {
+			referrers[subject.Digest] = append(referrers[subject.Digest], plainDescriptor(desc))
+		}
+	}
+
+	problems := make(map[digest.Digest]*Problem)
+	addProblem := func(kind string, desc ocispec.Descriptor, ref string) {
+		p, ok := problems[desc.Digest]
+		if !ok {
+			p = &Problem{Kind: kind, Descriptor: plainDescriptor(desc)}
+			problems[desc.Digest] = p
+		}
+		for _, r := range p.Refs {
+			if r == ref {
+				return
+			}
+		}
+		p.Refs = append(p.Refs, ref)
+	}
+
+	// Walk the graph of every tag, skipping the children of blobs that cannot be read
+	reachable := make(map[digest.Digest]bool)
+	for _, root := range idx.Manifests {
+		tag := root.Annotations[ocispec.AnnotationRefName]
+		if tag == "" {
+			continue
+		}
+
+		seen := make(map[digest.Digest]bool)
+		queue := []ocispec.Descriptor{root}
+		for len(queue) > 0 {
+			desc := queue[0]
+			queue = queue[1:]
+			if seen[desc.Digest] {
+				continue
+			}
+			seen[desc.Digest] = true
+			reachable[desc.Digest] = true
+
+			blob, found := blobs[desc.Digest]
+			switch {
+			case !found && desc.Digest == root.Digest:
+				addProblem(ProblemDangling, desc, tag)
+				continue
+			case !found:
+				addProblem(ProblemMissing, desc, tag)
+				continue
+			case !blob.ok:
+				addProblem(ProblemCorrupt, desc, tag)
+				continue
+			}
+
+			successors, err := fetch(desc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", desc.Digest, err)
+			}
+			queue = append(queue, successors...)
+			queue = append(queue, referrers[desc.Digest]...)
+		}
+	}
+
+	for d, blob := range blobs {
+		switch {
+		case !blob.ok && problems[d] == nil:
+			problems[d] = &Problem{Kind: ProblemCorrupt, Descriptor: ocispec.Descriptor{Digest: d, Size: blob.size}}
+		case !reachable[d]:
+			problems[d] = &Problem{Kind: ProblemOrphan, Descriptor: ocispec.Descriptor{Digest: d, Size: blob.size}}
+		}
+	}
+
+	for _, p := range problems {
+		sort.Strings(p.Refs)
+		report.Problems = append(report.Problems, *p)
+	}
+	sort.Slice(report.Problems, func(i, j int) bool {
+		a, b := report.Problems[i], report.Problems[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Descriptor.Digest < b.Descriptor.Digest
+	})
+	return report, nil
+}
+
+// plainDescriptor returns desc without its annotations and platform.
+func plainDescriptor(desc ocispec.Descriptor) ocispec.Descriptor {
+	return ocispec.Descriptor{MediaType: desc.MediaType, Digest: desc.Digest, Size: desc.Size}
+}
+
+// blobInfo is the result of checking a blob file.
+type blobInfo struct {
+	// ok reports whether the content matches the digest.
+	ok   bool
+	size int64
+}
+
+// hashBlobs re-hashes every blob in the store.
+func (s *Store) hashBlobs() (map[digest.Digest]blobInfo, error) {
+	blobs := make(map[digest.Digest]blobInfo)
+
+	blobsDir := filepath.Join(s.path, ocispec.ImageBlobsDir)
+	algs, err := os.ReadDir(blobsDir)
+	if os.IsNotExist(err) {
+		return blobs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blobs directory: %w", err)
+	}
+	for _, alg := range algs {
+		if !alg.IsDir() || !digest.Algorithm(alg.Name()).Available() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(blobsDir, alg.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blobs directory: %w", err)
+		}
+		for _, entry := range entries {
+			d := digest.NewDigestFromEncoded(digest.Algorithm(alg.Name()), entry.Name())
+			if entry.IsDir() || d.Validate() != nil {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat blob %s: %w", d, err)
+			}
+			ok, err := verifyFile(filepath.Join(blobsDir, alg.Name(), entry.Name()), d)
+			if err != nil {
+				return nil, err
+			}
+			blobs[d] = blobInfo{ok: ok, size: info.Size()}
+		}
+	}
+	return blobs, nil
+}
+
+// verifyFile reports whether the content of the file at path matches d.
+func verifyFile(path string, d digest.Digest) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open blob %s: %w", d, err)
+	}
+	defer f.Close()
+
+	verifier := d.Verifier()
+	if _, err := io.Copy(verifier, f); err != nil {
+		return false, fmt.Errorf("failed to read blob %s: %w", d, err)
+	}
+	return verifier.Verified(), nil
+}
+
+// Restore replaces the blob desc, which is missing or corrupt, with the content of r. The
+// content is verified against desc before it is stored.
+func (s *Store) Restore(ctx context.Context, desc ocispec.Descriptor, r io.Reader) error {
+	unlock, err := s.RLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	storage, err := oci.NewStorage(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	path := filepath.Join(s.path, ocispec.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove blob %s: %w", desc.Digest, err)
+	}
+	if err := storage.Push(ctx, desc, r); err != nil {
+		return fmt.Errorf("failed to restore blob %s: %w", desc.Digest, err)
+	}
+
+	// The index is unchanged, but the graph of the content it lists is not
+	s.mu.Lock()
+	s.oci = nil
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFsck(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	st, err := New(dir)
+	require.NoError(t, err)
+
+	build := func(name string) ocispec.Manifest {
+		srcDir := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: "+name+"\n---\n"), 0644))
+		ref := "localhost/" + name + ":v1"
+		require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+		desc, err := st.Resolve(ctx, ref)
+		require.NoError(t, err)
+		rc, err := st.Fetch(ctx, desc)
+		require.NoError(t, err)
+		defer rc.Close()
+		var manifest ocispec.Manifest
+		require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+		return manifest
+	}
+	blobPath := func(d digest.Digest) string {
+		return filepath.Join(dir, ocispec.ImageBlobsDir, d.Algorithm().String(), d.Encoded())
+	}
+	problemsOf := func(report *FsckReport) map[digest.Digest]Problem {
+		problems := make(map[digest.Digest]Problem)
+		for _, p := range report.Problems {
+			problems[p.Descriptor.Digest] = p
+		}
+		return problems
+	}
+
+	report, err := st.Fsck(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Problems)
+
+	// A skill with a corrupt layer
+	corrupt := build("corrupt")
+	layer := corrupt.Layers[0]
+	original, err := os.ReadFile(blobPath(layer.Digest))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(blobPath(layer.Digest), []byte("garbage"), 0644))
+
+	// A skill with a missing config
+	missing := build("missing")
+	config, err := os.ReadFile(blobPath(missing.Config.Digest))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(blobPath(missing.Config.Digest)))
+
+	// A tag without its manifest: its config and layer are orphans
+	dangling := build("dangling")
+	danglingDesc, err := st.Resolve(ctx, "localhost/dangling:v1")
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(blobPath(danglingDesc.Digest)))
+
+	report, err = st.Fsck(ctx)
+	require.NoError(t, err)
+	problems := problemsOf(report)
+	assert.Len(t, problems, 5)
+	assert.Equal(t, Problem{Kind: ProblemCorrupt, Descriptor: plainDescriptor(layer), Refs: []string{"localhost/corrupt:v1"}}, problems[layer.Digest])
+	assert.Equal(t, Problem{Kind: ProblemMissing, Descriptor: plainDescriptor(missing.Config), Refs: []string{"localhost/missing:v1"}}, problems[missing.Config.Digest])
+	assert.Equal(t, Problem{Kind: ProblemDangling, Descriptor: plainDescriptor(danglingDesc), Refs: []string{"localhost/dangling:v1"}}, problems[danglingDesc.Digest])
+	assert.Equal(t, ProblemOrphan, problems[dangling.Config.Digest].Kind)
+	assert.Equal(t, ProblemOrphan, problems[dangling.Layers[0].Digest].Kind)
+
+	// Content that does not match is not restored
+	assert.Error(t, st.Restore(ctx, layer, bytes.NewReader([]byte("garbage"))))
+
+	require.NoError(t, st.Restore(ctx, layer, bytes.NewReader(original)))
+	require.NoError(t, st.Restore(ctx, missing.Config, bytes.NewReader(config)))
+
+	report, err = st.Fsck(ctx)
+	require.NoError(t, err)
+	problems = problemsOf(report)
+	assert.Len(t, problems, 3)
+	assert.NotContains(t, problems, layer.Digest)
+	assert.NotContains(t, problems, missing.Config.Digest)
+
+	rc, err := st.Fetch(ctx, layer)
+	require.NoError(t, err)
+	restored, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	rc.Close()
+	assert.Equal(t, original, restored)
+}
+
+func TestFsckCorruptManifest(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	st, err := New(dir)
+	require.NoError(t, err)
+
+	srcDir := filepath.Join(t.TempDir(), "broken")
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: broken\ndescription: broken\n---\n"), 0644))
+	require.NoError(t, st.Build(ctx, srcDir, "localhost/broken:v1", nil))
+	desc, err := st.Resolve(ctx, "localhost/broken:v1")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ocispec.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded()), []byte("{"), 0644))
+
+	// The index can no longer be loaded, but it can still be checked
+	_, err = New(dir)
+	assert.Error(t, err)
+
+	opened, err := Open(dir)
+	require.NoError(t, err)
+	report, err := opened.Fsck(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Problems)
+	assert.Equal(t, ProblemCorrupt, report.Problems[0].Kind)
+	assert.Equal(t, desc.Digest, report.Problems[0].Descriptor.Digest)
+	assert.Equal(t, []string{"localhost/broken:v1"}, report.Problems[0].Refs)
+}
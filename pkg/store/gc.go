@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+// PruneOptions configures Prune.
+type PruneOptions struct {
+	// DryRun reports what would be removed, without removing anything.
+	DryRun bool
+	// OlderThan keeps unreachable blobs that were written more recently than this.
+	OlderThan time.Duration
+}
+
+// Prune removes the blobs that cannot be reached from a tag, and returns how many were (or,
+// with DryRun, would be) removed and their total size.
+//
+// Reachability is walked generically: from every tag, through the config, layers and
+// subject of manifests and the manifests of indexes, and to every referrer (such as a
+// signature or SBOM) whose subject is reachable.
+func (s *Store) Prune(ctx context.Context, opts PruneOptions) (int, int64, error) {
+	unlock, err := s.lock(ctx, lockFileName, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer unlock()
+
+	deletedCount := 0
+	var deletedSize int64
+	// The index is locked throughout, so no tag can be added to content about to be removed
+	err = s.write(ctx, func(o *oci.Store) error {
+		reachable, err := reachableBlobs(ctx, o)
+		if err != nil {
+			return err
+		}
+
+		// Manifests are also listed in index.json (by digest), and are deleted through the
+		// OCI store so that their entries are removed as well
+		indexed, err := s.indexedManifests()
+		if err != nil {
+			return err
+		}
+
+		blobsDir := filepath.Join(s.path, ocispec.ImageBlobsDir)
+		algs, err := os.ReadDir(blobsDir)
+		if os.IsNotExist(err) {
+			return nil // Nothing to prune
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read blobs directory: %w", err)
+		}
+
+		// Blobs are removed one by one, once they are known to be unreachable; the OCI store
+		// must not remove the successors of a manifest as well
+		o.AutoGC = false
+
+		cutoff := time.Now().Add(-opts.OlderThan)
+		for _, alg := range algs {
+			if !alg.IsDir() {
+				continue
+			}
+			entries, err := os.ReadDir(filepath.Join(blobsDir, alg.Name()))
+			if err != nil {
+				return fmt.Errorf("failed to read blobs directory: %w", err)
+			}
+
+			for _, entry := range entries {
+				if entry.IsDir() {
+					continue
+				}
+				d := digest.NewDigestFromEncoded(digest.Algorithm(alg.Name()), entry.Name())
+				if d.Validate() != nil || reachable[d] {
+					continue
+				}
+				info, err := entry.Info()
+				if err != nil {
+					return fmt.Errorf("failed to stat blob %s: %w", d, err)
+				}
+				if info.ModTime().After(cutoff) {
+					continue
+				}
+
+				if !opts.DryRun {
+					if desc, ok := indexed[d]; ok {
+						err = o.Delete(ctx, desc)
+					} else {
+						err = os.Remove(filepath.Join(blobsDir, alg.Name(), entry.Name()))
+					}
+					if err != nil {
+						return fmt.Errorf("failed to remove blob %s: %w", d, err)
+					}
+				}
+				deletedCount++
+				deletedSize += info.Size()
+			}
+		}
+		return nil
+	})
+	return deletedCount, deletedSize, err
+}
+
+// reachableBlobs returns the digests of every blob in o that can be reached from a tag.
+func reachableBlobs(ctx context.Context, o *oci.Store) (map[digest.Digest]bool, error) {
+	var tags []string
+	err := o.Tags(ctx, "", func(batch []string) error {
+		tags = append(tags, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	roots := make([]ocispec.Descriptor, 0, len(tags))
+	for _, tag := range tags {
+		desc, err := o.Resolve(ctx, tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", tag, err)
+		}
+		roots = append(roots, desc)
+	}
+	return reachableFrom(ctx, o, roots)
+}
+
+// reachableFrom returns the digests of every blob in o that can be reached from roots.
+func reachableFrom(ctx context.Context, o *oci.Store, roots []ocispec.Descriptor) (map[digest.Digest]bool, error) {
+	reachable := make(map[digest.Digest]bool)
+	var queue []ocispec.Descriptor
+	visit := func(desc ocispec.Descriptor) {
+		if !reachable[desc.Digest] {
+			reachable[desc.Digest] = true
+			queue = append(queue, desc)
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+
+	for len(queue) > 0 {
+		desc := queue[0]
+		queue = queue[1:]
+
+		// Config, layers and subject of manifests, and the manifests of indexes
+		successors, err := content.Successors(ctx, o, desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", desc.Digest, err)
+		}
+		for _, successor := range successors {
+			visit(successor)
+		}
+
+		// Referrers of the node: manifests that point to it as their subject
+		predecessors, err := o.Predecessors(ctx, desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find referrers of %s: %w", desc.Digest, err)
+		}
+		for _, predecessor := range predecessors {
+			if reachable[predecessor.Digest] {
+				continue
+			}
+			subject, err := subjectOf(ctx, o, predecessor)
+			if err != nil {
+				return nil, err
+			}
+			if subject != nil && subject.Digest == desc.Digest {
+				visit(predecessor)
+			}
+		}
+	}
+	return reachable, nil
+}
+
+// subjectOf returns the subject of the manifest or index desc, or nil if it has none.
+func subjectOf(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex:
+	default:
+		return nil, nil
+	}
+	data, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", desc.Digest, err)
+	}
+	var v struct {
+		Subject *ocispec.Descriptor `json:"subject,omitempty"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", desc.Digest, err)
+	}
+	return v.Subject, nil
+}
+
+// indexedManifests returns the manifests listed in index.json, by digest.
+func (s *Store) indexedManifests() (map[digest.Digest]ocispec.Descriptor, error) {
+	idx, err := readIndexFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	manifests := make(map[digest.Digest]ocispec.Descriptor, len(idx.Manifests))
+	for _, desc := range idx.Manifests {
+		manifests[desc.Digest] = plainDescriptor(desc)
+	}
+	return manifests, nil
+}
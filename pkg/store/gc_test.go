@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	build := func(name string) ocispec.Descriptor {
+		srcDir := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: "+name+"\n---\n"), 0644))
+		ref := "localhost/" + name + ":v1"
+		require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+		desc, err := st.Resolve(ctx, ref)
+		require.NoError(t, err)
+		return desc
+	}
+
+	// A tagged skill with a signature that refers to it
+	signed := build("signed")
+	signature, err := oras.PackManifest(ctx, st, oras.PackManifestVersion1_1, "application/vnd.example.signature", oras.PackManifestOptions{
+		Subject: &signed,
+	})
+	require.NoError(t, err)
+
+	// A skill that is only reachable through a tagged index
+	nested := build("nested")
+	indexBytes, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{nested},
+	})
+	require.NoError(t, err)
+	index, err := oras.PushBytes(ctx, st, ocispec.MediaTypeImageIndex, indexBytes)
+	require.NoError(t, err)
+	require.NoError(t, st.Tag(ctx, index, "localhost/bundle:v1"))
+	require.NoError(t, st.oci.Untag(ctx, "localhost/nested:v1"))
+
+	// A skill that is no longer tagged: its manifest, config and layer are garbage
+	orphan := build("orphan")
+	require.NoError(t, st.oci.Untag(ctx, "localhost/orphan:v1"))
+
+	count, _, err := st.Prune(ctx, PruneOptions{OlderThan: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "recent blobs are kept")
+
+	count, size, err := st.Prune(ctx, PruneOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Positive(t, size)
+	exists, err := st.Exists(ctx, orphan)
+	require.NoError(t, err)
+	assert.True(t, exists, "dry run deletes nothing")
+
+	count, _, err = st.Prune(ctx, PruneOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	for _, desc := range []ocispec.Descriptor{signed, signature, nested, index} {
+		exists, err := st.Exists(ctx, desc)
+		require.NoError(t, err)
+		assert.True(t, exists, "%s is reachable", desc.MediaType)
+	}
+	exists, err = st.Exists(ctx, orphan)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// The orphan is no longer listed in index.json
+	_, err = st.Resolve(ctx, orphan.Digest.String())
+	assert.Error(t, err)
+	indexed, err := st.indexedManifests()
+	require.NoError(t, err)
+	assert.NotContains(t, indexed, orphan.Digest)
+	assert.Contains(t, indexed, signature.Digest)
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// errStopWalk stops WalkLayer early, without an error.
+var errStopWalk = errors.New("stop walk")
+
+// WalkLayer calls fn for each entry of a skill layer (a gzipped tarball), without extracting
+// it. For regular files, content reads the file; it is empty for other entries. The Digest
+// of entries is not set. Entries whose path would leave the skill directory are an error.
+func WalkLayer(r io.Reader, fn func(entry FileInfo, content io.Reader) error) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read layer: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read layer: %w", err)
+		}
+
+		name := path.Clean(header.Name)
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("layer contains unsafe path: %s", header.Name)
+		}
+
+		entry := FileInfo{
+			Path: name,
+			Mode: fmt.Sprintf("%04o", header.FileInfo().Mode().Perm()),
+		}
+		var content io.Reader = strings.NewReader("")
+		switch header.Typeflag {
+		case tar.TypeDir:
+			entry.Type = "dir"
+		case tar.TypeSymlink:
+			entry.Type = "symlink"
+			entry.Target = header.Linkname
+		case tar.TypeReg:
+			entry.Type = "file"
+			entry.Size = header.Size
+			content = tr
+		default:
+			continue
+		}
+
+		if err := fn(entry, content); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadLayerFiles lists the entries of a skill layer. Files are hashed as they are read.
+func ReadLayerFiles(r io.Reader) ([]FileInfo, error) {
+	var files []FileInfo
+	err := WalkLayer(r, func(entry FileInfo, content io.Reader) error {
+		if entry.Type == "file" {
+			digester := digest.Canonical.Digester()
+			if _, err := io.Copy(digester.Hash(), content); err != nil {
+				return fmt.Errorf("failed to read %s: %w", entry.Path, err)
+			}
+			entry.Digest = digester.Digest().String()
+		}
+		files = append(files, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ReadLayerFile returns the content of the regular file at path (relative to the skill
+// directory, with forward slashes) in a skill layer.
+func ReadLayerFile(r io.Reader, path string) ([]byte, error) {
+	var data []byte
+	err := WalkLayer(r, func(entry FileInfo, content io.Reader) error {
+		if entry.Path != path {
+			return nil
+		}
+		if entry.Type != "file" {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+		var err error
+		if data, err = io.ReadAll(content); err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return errStopWalk
+	})
+	if errors.Is(err, errStopWalk) {
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%s not found in layer: %w", path, fs.ErrNotExist)
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tarLayer returns a skill layer with a regular file for every name.
+func tarLayer(t *testing.T, names ...string) []byte {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, name := range names {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: 2}))
+		_, err := tw.Write([]byte("hi"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func TestReadLayerFilesUnsafePath(t *testing.T) {
+	files, err := ReadLayerFiles(bytes.NewReader(tarLayer(t, "./SKILL.md", "docs/../README.md")))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "SKILL.md", files[0].Path)
+	assert.Equal(t, "README.md", files[1].Path)
+
+	for _, name := range []string{"../../../../x.md", "/etc/passwd", "docs/../../x.md"} {
+		_, err := ReadLayerFiles(bytes.NewReader(tarLayer(t, "SKILL.md", name)))
+		assert.ErrorContains(t, err, "unsafe path", name)
+	}
+}
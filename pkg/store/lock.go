@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+// The store is shared by every skr process of the user, so it is guarded by two file locks:
+//
+//   - lockFileName guards blobs. Writes that push blobs before tagging them (such as Build
+//     or a pull) hold it shared, so they can run side by side; Prune and Delete, which
+//     remove blobs, hold it exclusively, so they never remove the blobs of a write that has
+//     not been tagged yet.
+//   - indexLockFileName guards index.json, which the OCI store rewrites in place. It is held
+//     shared to read the index, and exclusively to change it. Changes are made to a copy of
+//     the index reloaded from disk, so they never overwrite the changes of another process.
+//
+// The blob lock is always taken before the index lock.
+const (
+	lockFileName      = "skr.lock"
+	indexLockFileName = "index.lock"
+)
+
+// lockRetryDelay is how often a lock held by another process is retried.
+const lockRetryDelay = 50 * time.Millisecond
+
+// lock locks the file name in the store directory, until unlock is called.
+func (s *Store) lock(ctx context.Context, name string, exclusive bool) (unlock func(), err error) {
+	fl := flock.New(filepath.Join(s.path, name))
+	tryLock := fl.TryRLockContext
+	if exclusive {
+		tryLock = fl.TryLockContext
+	}
+	if _, err := tryLock(ctx, lockRetryDelay); err != nil {
+		return nil, fmt.Errorf("failed to lock store: %w", err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// RLock keeps Prune and Delete from removing blobs until unlock is called. Hold it while
+// pushing the blobs of an artifact and tagging it.
+func (s *Store) RLock(ctx context.Context) (unlock func(), err error) {
+	return s.lock(ctx, lockFileName, false)
+}
+
+// indexStamp identifies a version of index.json.
+type indexStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s *Store) stampIndex() (indexStamp, error) {
+	fi, err := os.Stat(filepath.Join(s.path, ocispec.ImageIndexFile))
+	if os.IsNotExist(err) {
+		return indexStamp{}, nil
+	}
+	if err != nil {
+		return indexStamp{}, fmt.Errorf("failed to stat index: %w", err)
+	}
+	return indexStamp{modTime: fi.ModTime(), size: fi.Size()}, nil
+}
+
+// read returns the OCI store, reloading the index if another process changed it.
+func (s *Store) read(ctx context.Context) (*oci.Store, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock(ctx, indexLockFileName, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	stamp, err := s.stampIndex()
+	if err != nil {
+		return nil, err
+	}
+	if s.oci != nil && stamp == s.stamp {
+		return s.oci, nil
+	}
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+	return s.oci, nil
+}
+
+// write calls fn to change the index, on an OCI store freshly loaded from disk, while no
+// other process can read or change it.
+func (s *Store) write(ctx context.Context, fn func(o *oci.Store) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock(ctx, indexLockFileName, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.reload(ctx); err != nil {
+		return err
+	}
+	fnErr := fn(s.oci)
+
+	// Even if fn failed, it may have saved some changes
+	stamp, err := s.stampIndex()
+	if err != nil {
+		return err
+	}
+	s.stamp = stamp
+	return fnErr
+}
+
+// reload loads the index from disk. The index lock must be held.
+func (s *Store) reload(ctx context.Context) error {
+	stamp, err := s.stampIndex()
+	if err != nil {
+		return err
+	}
+	o, err := oci.NewWithContext(ctx, s.path)
+	if err != nil {
+		return fmt.Errorf("failed to load OCI store: %w", err)
+	}
+	s.oci, s.stamp = o, stamp
+	return nil
+}
+
+// readIndex reads index.json from disk.
+func (s *Store) readIndex(ctx context.Context) (*ocispec.Index, error) {
+	unlock, err := s.lock(ctx, indexLockFileName, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return readIndexFile(s.path)
+}
+
+// readIndexFile reads index.json in the store at path. The index lock must be held.
+func readIndexFile(path string) (*ocispec.Index, error) {
+	data, err := os.ReadFile(filepath.Join(path, ocispec.ImageIndexFile))
+	if os.IsNotExist(err) {
+		return &ocispec.Index{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	var idx ocispec.Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	return &idx, nil
+}
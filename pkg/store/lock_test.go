@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	stressProcesses  = 4
+	stressIterations = 10
+)
+
+// TestConcurrentProcesses runs builds, tags, deletes and prunes on one store from several
+// processes at once (see TestStoreHelperProcess), and checks that no change is lost.
+func TestConcurrentProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts several processes")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var cmds []*exec.Cmd
+	for i := range stressProcesses {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestStoreHelperProcess$")
+		cmd.Env = append(os.Environ(), "SKR_STORE_HELPER_DIR="+dir, "SKR_STORE_HELPER_ID="+strconv.Itoa(i))
+		cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+		require.NoError(t, cmd.Start())
+		cmds = append(cmds, cmd)
+	}
+	for _, cmd := range cmds {
+		require.NoError(t, cmd.Wait())
+	}
+
+	st, err := New(dir)
+	require.NoError(t, err)
+
+	// Every process deletes the even versions it built, once it has built the next one
+	var want []string
+	for i := range stressProcesses {
+		for j := 1; j < stressIterations; j += 2 {
+			want = append(want, fmt.Sprintf("localhost/p%d:v%d", i, j))
+		}
+		want = append(want, fmt.Sprintf("localhost/p%d:latest", i))
+	}
+	sort.Strings(want)
+
+	tags, err := st.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, tags)
+
+	for i := range stressProcesses {
+		latest, err := st.Resolve(ctx, fmt.Sprintf("localhost/p%d:latest", i))
+		require.NoError(t, err)
+		last, err := st.Resolve(ctx, fmt.Sprintf("localhost/p%d:v%d", i, stressIterations-1))
+		require.NoError(t, err)
+		assert.Equal(t, last.Digest, latest.Digest)
+	}
+
+	// Every tag resolves to a complete artifact
+	for _, tag := range tags {
+		desc, err := st.Resolve(ctx, tag)
+		require.NoError(t, err)
+		rc, err := st.Fetch(ctx, desc)
+		require.NoError(t, err, tag)
+		var manifest ocispec.Manifest
+		require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+		rc.Close()
+		for _, blob := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+			exists, err := st.Exists(ctx, blob)
+			require.NoError(t, err)
+			assert.True(t, exists, "%s of %s", blob.MediaType, tag)
+		}
+	}
+
+	// index.json lists no deleted manifest
+	indexed, err := st.indexedManifests()
+	require.NoError(t, err)
+	for _, desc := range indexed {
+		exists, err := st.Exists(ctx, desc)
+		require.NoError(t, err)
+		assert.True(t, exists, "%s is listed in index.json", desc.Digest)
+	}
+}
+
+// TestStoreHelperProcess is run by TestConcurrentProcesses in a separate process.
+func TestStoreHelperProcess(t *testing.T) {
+	dir := os.Getenv("SKR_STORE_HELPER_DIR")
+	if dir == "" {
+		t.Skip("only run by TestConcurrentProcesses")
+	}
+	id := os.Getenv("SKR_STORE_HELPER_ID")
+	ctx := context.Background()
+
+	st, err := New(dir)
+	require.NoError(t, err)
+
+	repo := "localhost/p" + id
+	for j := range stressIterations {
+		name := fmt.Sprintf("p%s-v%d", id, j)
+		srcDir := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: stress\n---\n"), 0644))
+
+		ref := fmt.Sprintf("%s:v%d", repo, j)
+		require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+		desc, err := st.Resolve(ctx, ref)
+		require.NoError(t, err)
+		require.NoError(t, st.Tag(ctx, desc, repo+":latest"))
+
+		if j%2 == 1 {
+			prev, err := st.Resolve(ctx, fmt.Sprintf("%s:v%d", repo, j-1))
+			require.NoError(t, err)
+			require.NoError(t, st.Delete(ctx, prev))
+		}
+		if j%3 == 0 {
+			_, _, err := st.Prune(ctx, PruneOptions{})
+			require.NoError(t, err)
+		}
+	}
+}
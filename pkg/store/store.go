@@ -11,9 +11,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/adrg/xdg"
+	"github.com/andrewhowdencom/skr/pkg/skill"
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2/content"
@@ -21,17 +24,48 @@ import (
 )
 
 const (
+	// ArtifactType is the artifactType of skill manifests.
+	ArtifactType         = "application/vnd.agentskills.skill.v1"
 	MediaTypeSkillLayer  = "application/vnd.agentskills.skill.layer.v1+tar+gzip"
 	MediaTypeSkillConfig = "application/vnd.agentskills.skill.config.v1+json"
 	StoreDirName         = "skr/store"
+
+	// AnnotationExecutables lists (as a JSON array) the files in a layer that have an executable bit set.
+	AnnotationExecutables = "com.skr.executables"
+	// AnnotationSymlinks maps (as a JSON object) symlinks in a layer to their targets.
+	AnnotationSymlinks = "com.skr.symlinks"
+	// AnnotationExtensions holds (as a JSON object) the frontmatter fields skr does not
+	// interpret itself, such as license, tags or homepage. See skill.Skill.ExtensionFields.
+	AnnotationExtensions = "com.skr.extensions"
 )
 
 type Store struct {
 	path string
-	oci  *oci.Store
+
+	// mu guards oci, which is replaced whenever the index is reloaded (see read and write).
+	mu    sync.Mutex
+	oci   *oci.Store
+	stamp indexStamp
 }
 
 func New(path string) (*Store, error) {
+	s, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first load creates the OCI layout files if they are missing, so no other process
+	// may read them meanwhile
+	if err := s.write(context.Background(), func(*oci.Store) error { return nil }); err != nil {
+		return nil, fmt.Errorf("failed to initialize OCI store: %w", err)
+	}
+	return s, nil
+}
+
+// Open returns the store at path (or the system store, if path is empty) like New, but
+// does not load its index until it is first used. Use it to check a store whose index
+// cannot be loaded, such as one with a corrupt manifest (see Fsck).
+func Open(path string) (*Store, error) {
 	if path == "" {
 		dataPath, err := xdg.DataFile(StoreDirName)
 		if err != nil {
@@ -44,25 +78,48 @@ func New(path string) (*Store, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create store directory: %w", err)
 	}
+	return &Store{path: path}, nil
+}
 
-	ociStore, err := oci.New(path)
+// Build packages the skill in srcDir as an artifact, and tags it as tag (if not empty).
+//
+// The manifest is annotated with the skill's metadata (see skillAnnotations) and its creation
+// time; annotations are added on top of these, and may override them. The config blob holds a
+// SkillConfig.
+func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotations map[string]string) error {
+	// Blobs are pushed before the manifest that references them is tagged, so they must not
+	// be pruned in between
+	unlock, err := s.RLock(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize OCI store: %w", err)
+		return err
 	}
+	defer unlock()
 
-	return &Store{
-		path: path,
-		oci:  ociStore,
-	}, nil
-}
+	sk, err := skill.Load(srcDir)
+	if err != nil {
+		return fmt.Errorf("failed to load skill: %w", err)
+	}
+
+	frontmatter, err := readFrontmatter(srcDir)
+	if err != nil {
+		return err
+	}
 
-func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotations map[string]string) error {
 	// 1. Create a tarball of the directory
 	buf := &bytes.Buffer{}
 	gw := gzip.NewWriter(buf)
 	tw := tar.NewWriter(gw)
 
-	err := filepath.Walk(srcDir, func(file string, fi os.FileInfo, err error) error {
+	var executables []string
+	var files []FileInfo
+	symlinks := make(map[string]string)
+
+	realSrcDir, err := filepath.EvalSymlinks(srcDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve source directory: %w", err)
+	}
+
+	err = filepath.Walk(srcDir, func(file string, fi os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -76,8 +133,29 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 		if relPath == "." {
 			return nil
 		}
+		relPath = filepath.ToSlash(relPath)
+
+		// Symlinks are preserved as links, as long as they stay inside the skill.
+		var link string
+		if fi.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(file)
+			if err != nil {
+				return err
+			}
+			if err := skill.CheckLink(relPath, link); err != nil {
+				return err
+			}
+			if resolved, err := filepath.EvalSymlinks(file); err == nil {
+				if rel, err := filepath.Rel(realSrcDir, resolved); err != nil || !filepath.IsLocal(rel) {
+					return fmt.Errorf("symlink %s points outside the skill directory (%s)", relPath, link)
+				}
+			}
+			symlinks[relPath] = link
+		} else if !fi.IsDir() && !fi.Mode().IsRegular() {
+			return fmt.Errorf("unsupported file type for %s: %s", relPath, fi.Mode().Type())
+		}
 
-		header, err := tar.FileInfoHeader(fi, fi.Name())
+		header, err := tar.FileInfoHeader(fi, link)
 		if err != nil {
 			return err
 		}
@@ -88,16 +166,37 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 			return err
 		}
 
-		if !fi.IsDir() {
+		entry := FileInfo{Path: relPath, Mode: fmt.Sprintf("%04o", fi.Mode().Perm())}
+		switch {
+		case link != "":
+			entry.Type = "symlink"
+			entry.Target = link
+		case fi.IsDir():
+			entry.Type = "dir"
+		default:
+			entry.Type = "file"
+			entry.Size = fi.Size()
+		}
+
+		if fi.Mode().IsRegular() {
+			if fi.Mode().Perm()&0111 != 0 {
+				executables = append(executables, relPath)
+			}
+
 			data, err := os.Open(file)
 			if err != nil {
 				return err
 			}
 			defer data.Close()
-			if _, err := io.Copy(tw, data); err != nil {
+
+			digester := digest.Canonical.Digester()
+			if _, err := io.Copy(io.MultiWriter(tw, digester.Hash()), data); err != nil {
 				return err
 			}
+			entry.Digest = digester.Digest().String()
 		}
+
+		files = append(files, entry)
 		return nil
 	})
 
@@ -118,9 +217,26 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 
 	// 2. Push layer to store
 	layerDesc := ocispec.Descriptor{
-		MediaType: MediaTypeSkillLayer,
-		Digest:    layerDigest,
-		Size:      layerSize,
+		MediaType:   MediaTypeSkillLayer,
+		Digest:      layerDigest,
+		Size:        layerSize,
+		Annotations: make(map[string]string),
+	}
+
+	// Record special files so consumers can check them without unpacking the layer
+	if len(executables) > 0 {
+		executablesJSON, err := json.Marshal(executables)
+		if err != nil {
+			return fmt.Errorf("failed to marshal executables: %w", err)
+		}
+		layerDesc.Annotations[AnnotationExecutables] = string(executablesJSON)
+	}
+	if len(symlinks) > 0 {
+		symlinksJSON, err := json.Marshal(symlinks)
+		if err != nil {
+			return fmt.Errorf("failed to marshal symlinks: %w", err)
+		}
+		layerDesc.Annotations[AnnotationSymlinks] = string(symlinksJSON)
 	}
 
 	err = s.pushBlob(ctx, layerDesc, bytes.NewReader(layerBytes))
@@ -128,11 +244,26 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 		return fmt.Errorf("failed to push layer: %w", err)
 	}
 
-	// 3. Create and push config (empty for now)
-	config := map[string]string{
-		"created": time.Now().UTC().Format(time.RFC3339),
+	// 3. Create and push config
+	created := time.Now().UTC().Truncate(time.Second)
+
+	manifestAnnotations, err := skillAnnotations(sk)
+	if err != nil {
+		return err
+	}
+	manifestAnnotations[ocispec.AnnotationCreated] = created.Format(time.RFC3339)
+	for k, v := range annotations {
+		manifestAnnotations[k] = v
+	}
+
+	config := newSkillConfig(sk, frontmatter, files, created)
+	config.Build.Source = manifestAnnotations[ocispec.AnnotationSource]
+	config.Build.Revision = manifestAnnotations[ocispec.AnnotationRevision]
+
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
 	}
-	configBytes, _ := json.Marshal(config)
 	configDigest := digest.FromBytes(configBytes)
 	configDesc := ocispec.Descriptor{
 		MediaType: MediaTypeSkillConfig,
@@ -146,9 +277,11 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 
 	// 4. Create and push Manifest
 	manifest := ocispec.Manifest{
-		Config:      configDesc,
-		Layers:      []ocispec.Descriptor{layerDesc},
-		Annotations: annotations,
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: ArtifactType,
+		Config:       configDesc,
+		Layers:       []ocispec.Descriptor{layerDesc},
+		Annotations:  manifestAnnotations,
 	}
 	manifest.SchemaVersion = 2
 
@@ -158,9 +291,10 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 	}
 
 	manifestDesc := ocispec.Descriptor{
-		MediaType: ocispec.MediaTypeImageManifest,
-		Digest:    digest.FromBytes(manifestBytes),
-		Size:      int64(len(manifestBytes)),
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: ArtifactType,
+		Digest:       digest.FromBytes(manifestBytes),
+		Size:         int64(len(manifestBytes)),
 	}
 
 	err = s.pushBlob(ctx, manifestDesc, bytes.NewReader(manifestBytes))
@@ -170,7 +304,7 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 
 	// 5. Tag the manifest
 	if tag != "" {
-		err = s.oci.Tag(ctx, manifestDesc, tag)
+		err = s.Tag(ctx, manifestDesc, tag)
 		if err != nil {
 			return fmt.Errorf("failed to tag artifact: %w", err)
 		}
@@ -181,13 +315,21 @@ func (s *Store) Build(ctx context.Context, srcDir string, tag string, annotation
 
 // Fetch retrieves content by digest
 func (s *Store) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
-	return s.oci.Fetch(ctx, target)
+	o, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return o.Fetch(ctx, target)
 }
 
 // List returns a list of all tags in the store
 func (s *Store) List(ctx context.Context) ([]string, error) {
+	o, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var tags []string
-	err := s.oci.Tags(ctx, "", func(tagsList []string) error {
+	err = o.Tags(ctx, "", func(tagsList []string) error {
 		tags = append(tags, tagsList...)
 		return nil
 	})
@@ -197,89 +339,17 @@ func (s *Store) List(ctx context.Context) ([]string, error) {
 	return tags, nil
 }
 
-// Resolve resolves a reference (tag/digest) to a descriptor
+// Resolve resolves a reference (tag/digest) to a descriptor. References by digest
+// ("repo@sha256:...") are resolved by their digest.
 func (s *Store) Resolve(ctx context.Context, ref string) (ocispec.Descriptor, error) {
-	return s.oci.Resolve(ctx, ref)
-}
-
-// Prune removes all unreferenced blobs from the store
-func (s *Store) Prune(ctx context.Context) (int, int64, error) {
-	// 1. Identify all reachable blobs
-	reachable := make(map[string]bool)
-
-	err := s.oci.Tags(ctx, "", func(tags []string) error {
-		for _, tag := range tags {
-			// Resolve tag to manifest descriptor
-			desc, err := s.oci.Resolve(ctx, tag)
-			if err != nil {
-				return err
-			}
-			reachable[desc.Digest.String()] = true
-
-			// Fetch and parse manifest to find children (config + layers)
-			rc, err := s.oci.Fetch(ctx, desc)
-			if err != nil {
-				return err
-			}
-			manifestBytes, err := io.ReadAll(rc)
-			rc.Close()
-			if err != nil {
-				return err
-			}
-
-			var manifest ocispec.Manifest
-			if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
-				return err
-			}
-
-			// Mark config as reachable
-			reachable[manifest.Config.Digest.String()] = true
-
-			// Mark layers as reachable
-			for _, layer := range manifest.Layers {
-				reachable[layer.Digest.String()] = true
-			}
-		}
-		return nil
-	})
+	o, err := s.read(ctx)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to traverse tags: %w", err)
+		return ocispec.Descriptor{}, err
 	}
-
-	// 2. Iterate through all blobs in storage and delete unreachable ones
-	blobsDir := filepath.Join(s.path, "blobs", "sha256")
-	entries, err := os.ReadDir(blobsDir)
-	if os.IsNotExist(err) {
-		return 0, 0, nil // Nothing to prune
-	}
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to read blobs directory: %w", err)
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		ref = ref[i+1:]
 	}
-
-	deletedCount := 0
-	var deletedSize int64
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		digestStr := "sha256:" + entry.Name()
-		if !reachable[digestStr] {
-			info, err := entry.Info()
-			if err == nil {
-				deletedSize += info.Size()
-			}
-
-			path := filepath.Join(blobsDir, entry.Name())
-			if err := os.Remove(path); err != nil {
-				return deletedCount, deletedSize, fmt.Errorf("failed to remove blob %s: %w", path, err)
-			}
-			deletedCount++
-		}
-	}
-
-	return deletedCount, deletedSize, nil
+	return o.Resolve(ctx, ref)
 }
 
 // interface guard
@@ -287,32 +357,109 @@ var _ content.Storage = &oci.Store{}
 
 // Exists checks if a target descriptor exists in the store
 func (s *Store) Exists(ctx context.Context, target ocispec.Descriptor) (bool, error) {
-	return s.oci.Exists(ctx, target)
+	o, err := s.read(ctx)
+	if err != nil {
+		return false, err
+	}
+	return o.Exists(ctx, target)
 }
 
-// Push pushes content to the store
+// Push pushes content to the store. Manifests are also added to the index.
 func (s *Store) Push(ctx context.Context, desc ocispec.Descriptor, r io.Reader) error {
-	return s.oci.Push(ctx, desc, r)
+	if isManifest(desc) {
+		return s.write(ctx, func(o *oci.Store) error {
+			return o.Push(ctx, desc, r)
+		})
+	}
+	o, err := s.read(ctx)
+	if err != nil {
+		return err
+	}
+	return o.Push(ctx, desc, r)
 }
 
 // Tag aliases a descriptor with a reference
 func (s *Store) Tag(ctx context.Context, desc ocispec.Descriptor, reference string) error {
-	return s.oci.Tag(ctx, desc, reference)
+	return s.write(ctx, func(o *oci.Store) error {
+		return o.Tag(ctx, desc, reference)
+	})
 }
 
-// Delete removes a descriptor from the store
+// Untag removes the tag reference. The manifest it refers to is kept while other tags
+// refer to it; otherwise it is deleted like Delete does, and deleted is true.
+func (s *Store) Untag(ctx context.Context, reference string) (deleted bool, err error) {
+	unlock, err := s.lock(ctx, lockFileName, true)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	err = s.write(ctx, func(o *oci.Store) error {
+		desc, err := o.Resolve(ctx, reference)
+		if err != nil {
+			return err
+		}
+		if err := o.Untag(ctx, reference); err != nil {
+			return err
+		}
+
+		var tags []string
+		if err := o.Tags(ctx, "", func(page []string) error {
+			tags = append(tags, page...)
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			other, err := o.Resolve(ctx, tag)
+			if err != nil {
+				return err
+			}
+			if other.Digest == desc.Digest {
+				return nil
+			}
+		}
+
+		deleted = true
+		return o.Delete(ctx, desc)
+	})
+	return deleted, err
+}
+
+// Delete removes a descriptor from the store, along with the blobs and referrers that are
+// no longer used.
 func (s *Store) Delete(ctx context.Context, target ocispec.Descriptor) error {
-	return s.oci.Delete(ctx, target)
+	unlock, err := s.lock(ctx, lockFileName, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.write(ctx, func(o *oci.Store) error {
+		return o.Delete(ctx, target)
+	})
 }
 
 // pushBlob pushes content if it doesn't already exist
 func (s *Store) pushBlob(ctx context.Context, desc ocispec.Descriptor, r io.Reader) error {
-	exists, err := s.oci.Exists(ctx, desc)
+	exists, err := s.Exists(ctx, desc)
 	if err != nil {
 		return err
 	}
 	if exists {
 		return nil
 	}
-	return s.oci.Push(ctx, desc, r)
+	return s.Push(ctx, desc, r)
+}
+
+// isManifest reports whether desc is a manifest or index, which the OCI store indexes.
+func isManifest(desc ocispec.Descriptor) bool {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex,
+		"application/vnd.docker.distribution.manifest.v2+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.oci.artifact.manifest.v1+json":
+		return true
+	}
+	return false
 }
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUntag(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	srcDir := filepath.Join(t.TempDir(), "my-skill")
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: test\n---\n"), 0644))
+	require.NoError(t, st.Build(ctx, srcDir, "localhost/my-skill:v1", nil))
+	desc, err := st.Resolve(ctx, "localhost/my-skill:v1")
+	require.NoError(t, err)
+	require.NoError(t, st.Tag(ctx, desc, "ghcr.io/acme/my-skill:v1"))
+
+	// The manifest is kept while another tag refers to it
+	deleted, err := st.Untag(ctx, "localhost/my-skill:v1")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	tags, err := st.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghcr.io/acme/my-skill:v1"}, tags)
+	exists, err := st.Exists(ctx, desc)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	deleted, err = st.Untag(ctx, "ghcr.io/acme/my-skill:v1")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	tags, err = st.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+	exists, err = st.Exists(ctx, desc)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = st.Untag(ctx, "ghcr.io/acme/my-skill:v1")
+	assert.Error(t, err)
+}
+
+func TestArtifacts(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	build := func(description, ref string) {
+		srcDir := filepath.Join(t.TempDir(), "my-skill")
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: "+description+"\n---\n"), 0644))
+		require.NoError(t, st.Build(ctx, srcDir, ref, nil))
+	}
+	build("first", "localhost/my-skill:v1")
+	old, err := st.Resolve(ctx, "localhost/my-skill:v1")
+	require.NoError(t, err)
+	// Building the tag again leaves the first build dangling
+	build("second", "localhost/my-skill:v1")
+	desc, err := st.Resolve(ctx, "localhost/my-skill:v1")
+	require.NoError(t, err)
+	require.NoError(t, st.Tag(ctx, desc, "ghcr.io/acme/my-skill:v1"))
+
+	artifacts, err := st.Artifacts(ctx)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 3)
+
+	assert.Equal(t, "ghcr.io/acme/my-skill:v1", artifacts[0].Ref)
+	assert.Equal(t, "localhost/my-skill:v1", artifacts[1].Ref)
+	assert.Equal(t, desc.Digest, artifacts[1].Descriptor.Digest)
+	assert.Equal(t, "my-skill", artifacts[1].Name)
+	assert.False(t, artifacts[1].Created.IsZero())
+	assert.Greater(t, artifacts[1].Size, desc.Size)
+	assert.False(t, artifacts[1].Dangling)
+	assert.NoError(t, artifacts[1].Err)
+
+	assert.Empty(t, artifacts[2].Ref)
+	assert.Equal(t, old.Digest, artifacts[2].Descriptor.Digest)
+	assert.True(t, artifacts[2].Dangling)
+
+	_, _, err = st.Prune(ctx, PruneOptions{})
+	require.NoError(t, err)
+	artifacts, err = st.Artifacts(ctx)
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+}
+
+func TestArtifactsWithoutArtifactType(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	st, err := New(dir)
+	require.NoError(t, err)
+
+	for _, description := range []string{"first", "second"} {
+		srcDir := filepath.Join(t.TempDir(), "my-skill")
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: "+description+"\n---\n"), 0644))
+		require.NoError(t, st.Build(ctx, srcDir, "localhost/my-skill:latest", nil))
+	}
+
+	// Pulled manifests are recorded without an artifactType
+	indexPath := filepath.Join(dir, ocispec.ImageIndexFile)
+	data, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	var idx ocispec.Index
+	require.NoError(t, json.Unmarshal(data, &idx))
+	for i := range idx.Manifests {
+		idx.Manifests[i].ArtifactType = ""
+	}
+	data, err = json.Marshal(idx)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(indexPath, data, 0644))
+
+	artifacts, err := st.Artifacts(ctx)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 2)
+	assert.Equal(t, "localhost/my-skill:latest", artifacts[0].Ref)
+	assert.True(t, artifacts[1].Dangling)
+	assert.Equal(t, "my-skill", artifacts[1].Name)
+}
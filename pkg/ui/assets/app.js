@@ -7,15 +7,26 @@ document.addEventListener('DOMContentLoaded', () => {
     const modal = document.getElementById('detail-modal');
     const modalTitle = document.getElementById('modal-title');
     const modalDesc = document.getElementById('modal-desc');
+    const modalMeta = document.getElementById('modal-meta');
     const installCmd = document.getElementById('install-cmd');
+    const modalPage = document.getElementById('modal-page');
 
     // Global state
     let allSkills = [];
     const host = window.location.host; // e.g. localhost:8080
+    let registryHost = host; // Where skills are installed from (the index may say otherwise)
 
     // Fetch Orchestration
     async function loadSkills() {
         try {
+            // 0. Prefer the precomputed search index (skr http generate / serve)
+            const index = await loadIndex();
+            if (index) {
+                allSkills = index;
+                renderSkills(allSkills);
+                return;
+            }
+
             // 1. Fetch Catalog
             const catalogRes = await fetch('/v2/_catalog');
             if (!catalogRes.ok) throw new Error(`Catalog fetch failed: ${catalogRes.status}`);
@@ -49,11 +60,21 @@ document.addEventListener('DOMContentLoaded', () => {
                     const annotations = manifest.annotations || {};
                     const shortName = repo.split('/').pop();
 
+                    // Frontmatter fields skr does not interpret (license, tags, homepage, ...)
+                    let extensions = {};
+                    try {
+                        extensions = JSON.parse(annotations['com.skr.extensions'] || '{}');
+                    } catch (e) {
+                        console.warn(`Invalid extension fields for ${repo}:`, e);
+                    }
+
                     return {
                         id: repo,
                         name: shortName,
-                        description: annotations['com.skr.description'] || 'No description available.',
-                        author: annotations['com.skr.author'] || 'Unknown Author',
+                        // Standard OCI annotations, falling back to the keys of older skr versions
+                        description: annotations['org.opencontainers.image.description'] || annotations['com.skr.description'] || 'No description available.',
+                        author: annotations['org.opencontainers.image.authors'] || annotations['com.skr.author'] || 'Unknown Author',
+                        extensions: extensions,
                         versions: tags.map(t => ({ version: t, tag: t })), // For now version == tag
                         latestTag: latestTag
                     };
@@ -75,18 +96,53 @@ document.addEventListener('DOMContentLoaded', () => {
         }
     }
 
-    // Search
+    // Load the search index, or return null if there is none (e.g. a plain registry)
+    async function loadIndex() {
+        try {
+            const res = await fetch('index.json');
+            if (!res.ok) return null;
+            const index = await res.json();
+            if (index.registry) registryHost = index.registry;
+
+            return (index.skills || []).map(skill => ({
+                id: skill.repository,
+                name: skill.name,
+                description: skill.description || 'No description available.',
+                author: skill.author || 'Unknown Author',
+                extensions: skill.extensions || {},
+                versions: (skill.versions || []).map(v => ({ version: v.tag, tag: v.tag })),
+                latestTag: skill.latest,
+                tokens: skill.tokens || [],
+                hasPage: true // skr http generate / serve render a page for every skill in the index
+            }));
+        } catch (e) {
+            console.warn('Search index unavailable, listing the registry instead:', e);
+            return null;
+        }
+    }
+
+    // Search: every word must appear in the name, description, author or repository, or
+    // start a word of the SKILL.md body (when the index has one).
     searchInput.addEventListener('input', (e) => {
-        const term = e.target.value.toLowerCase();
+        const terms = e.target.value.toLowerCase().split(/\s+/).filter(t => t);
         const filtered = allSkills.filter(skill => {
-            return (skill.name && skill.name.toLowerCase().includes(term)) ||
-                (skill.description && skill.description.toLowerCase().includes(term)) ||
-                (skill.author && skill.author.toLowerCase().includes(term)) ||
-                (skill.id && skill.id.toLowerCase().includes(term));
+            const text = [skill.name, skill.description, skill.author, skill.id].join('\n').toLowerCase();
+            return terms.every(term =>
+                text.includes(term) || hasToken(skill.tokens || [], term));
         });
         renderSkills(filtered);
     });
 
+    // Tokens are sorted, so a binary search finds the first one that could start with prefix
+    function hasToken(tokens, prefix) {
+        let lo = 0, hi = tokens.length;
+        while (lo < hi) {
+            const mid = (lo + hi) >> 1;
+            if (tokens[mid] < prefix) lo = mid + 1; else hi = mid;
+        }
+        return lo < tokens.length && tokens[lo].startsWith(prefix);
+    }
+
     // Render
     function renderSkills(skills) {
         grid.innerHTML = '';
@@ -145,18 +201,54 @@ document.addEventListener('DOMContentLoaded', () => {
     window.openModal = function (skill) {
         modalTitle.innerText = skill.name;
         modalDesc.innerText = skill.description;
+        renderExtensions(skill.extensions || {});
 
         // Construct install command
         // Convention: host/repo:tag
         // Note: oras pull host/repo:tag
         // skr install host/repo:tag
 
-        const cmd = `skr install ${host}/${skill.id}:${skill.latestTag}`;
+        const cmd = `skr install ${registryHost}/${skill.id}:${skill.latestTag}`;
         installCmd.innerText = cmd;
 
+        modalPage.classList.toggle('hidden', !skill.hasPage);
+        modalPage.href = `skills/${skill.id}/`;
+
         modal.classList.add('open');
     }
 
+    // Extension fields are shown as a definition list. Homepage-like URLs become links.
+    function renderExtensions(extensions) {
+        const keys = Object.keys(extensions).sort();
+        modalMeta.innerHTML = '';
+        modalMeta.classList.toggle('hidden', keys.length === 0);
+
+        keys.forEach(key => {
+            const value = extensions[key];
+            const dt = document.createElement('dt');
+            dt.innerText = key;
+            const dd = document.createElement('dd');
+
+            if (Array.isArray(value)) {
+                dd.innerHTML = value.map(v => `<span class="chip">${escapeHtml(String(v))}</span>`).join('');
+            } else if (typeof value === 'string' && /^https?:\/\//.test(value)) {
+                const a = document.createElement('a');
+                a.href = value;
+                a.target = '_blank';
+                a.rel = 'noopener noreferrer';
+                a.innerText = value;
+                dd.appendChild(a);
+            } else if (typeof value === 'object' && value !== null) {
+                dd.innerText = JSON.stringify(value);
+            } else {
+                dd.innerText = String(value);
+            }
+
+            modalMeta.appendChild(dt);
+            modalMeta.appendChild(dd);
+        });
+    }
+
     window.closeModal = function () {
         modal.classList.remove('open');
     }
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andrewhowdencom/skr/pkg/diff"
+	"github.com/andrewhowdencom/skr/pkg/index"
+	"github.com/andrewhowdencom/skr/pkg/store"
+)
+
+// DiffPage shows the changes between two versions of a skill.
+type DiffPage struct {
+	Root     string
+	Skill    index.Skill
+	From, To string
+	Diff     *diff.Diff
+}
+
+// DiffLine is a line of a unified diff, with the class it is shown with.
+type DiffLine struct {
+	Class string
+	Text  string
+}
+
+// DiffPath returns the path of the page of the changes from one tag of the skill in repo to
+// another, relative to the site root.
+func DiffPath(repo, from, to string) string {
+	return SkillPath(repo) + "diff/" + from + "/" + to + ".html"
+}
+
+// LoadDiff compares two tags of s in st.
+func LoadDiff(ctx context.Context, st *store.Store, s index.Skill, from, to string) (*DiffPage, error) {
+	a, err := loadArtifact(ctx, st, s, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := loadArtifact(ctx, st, s, to)
+	if err != nil {
+		return nil, err
+	}
+	return newDiffPage(s, a, b)
+}
+
+// LoadDiffs compares every pair of tags of s in st, in both directions.
+func LoadDiffs(ctx context.Context, st *store.Store, s index.Skill) ([]*DiffPage, error) {
+	artifacts := make([]*diff.Artifact, len(s.Versions))
+	for i, v := range s.Versions {
+		a, err := loadArtifact(ctx, st, s, v.Tag)
+		if err != nil {
+			return nil, err
+		}
+		artifacts[i] = a
+	}
+
+	var pages []*DiffPage
+	for i := range artifacts {
+		for j := range artifacts {
+			if i == j {
+				continue
+			}
+			page, err := newDiffPage(s, artifacts[i], artifacts[j])
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, page)
+		}
+	}
+	return pages, nil
+}
+
+func loadArtifact(ctx context.Context, st *store.Store, s index.Skill, tag string) (*diff.Artifact, error) {
+	ref := s.Repository + ":" + tag
+	desc, err := st.Resolve(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return diff.Load(ctx, st, ref, desc)
+}
+
+func newDiffPage(s index.Skill, from, to *diff.Artifact) (*DiffPage, error) {
+	d, err := diff.Compare(from, to)
+	if err != nil {
+		return nil, err
+	}
+	_, fromTag := index.SplitRef(from.Ref)
+	_, toTag := index.SplitRef(to.Ref)
+	return &DiffPage{
+		Root:  rootFrom(DiffPath(s.Repository, fromTag, toTag)),
+		Skill: s,
+		From:  fromTag,
+		To:    toTag,
+		Diff:  d,
+	}, nil
+}
+
+// diffLines splits the unified diff of a file into lines to show.
+func diffLines(unified string) []DiffLine {
+	var lines []DiffLine
+	// Only the lines before the first hunk are file headers: "---" in a hunk is a removed
+	// "--" line (e.g. frontmatter), not a header
+	header := true
+	for _, text := range strings.Split(strings.TrimSuffix(unified, "\n"), "\n") {
+		class := "diff-context"
+		switch {
+		case strings.HasPrefix(text, "@@"):
+			header = false
+			class = "diff-hunk"
+		case header:
+			class = "diff-file"
+		case strings.HasPrefix(text, "+"):
+			class = "diff-add"
+		case strings.HasPrefix(text, "-"):
+			class = "diff-del"
+		}
+		lines = append(lines, DiffLine{Class: class, Text: text})
+	}
+	return lines
+}
+
+// WriteDiffPage renders the page of the changes between two versions of a skill.
+func WriteDiffPage(w io.Writer, p *DiffPage) error {
+	return templates.ExecuteTemplate(w, "diff.html", p)
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// policy sanitizes rendered markdown. Skills are published by third parties, so raw HTML in
+// SKILL.md must not be able to run scripts in the registry UI.
+var policy = bluemonday.UGCPolicy()
+
+// RenderMarkdown renders the markdown body of a SKILL.md to sanitized HTML. Relative links
+// to files of the skill are rewritten with fileURL, which is given the path of the file
+// relative to the skill directory and returns "" to leave a link as it is. Links that leave
+// the skill are not rewritten.
+func RenderMarkdown(src []byte, fileURL func(path string) string) (template.HTML, error) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithASTTransformers(
+			util.Prioritized(&linkRewriter{fileURL: fileURL}, 100),
+		)),
+	)
+
+	var buf bytes.Buffer
+	if err := md.Convert(src, &buf); err != nil {
+		return "", err
+	}
+	return template.HTML(policy.SanitizeBytes(buf.Bytes())), nil
+}
+
+// linkRewriter points relative links at the pages of the files they refer to.
+type linkRewriter struct {
+	fileURL func(path string) string
+}
+
+func (l *linkRewriter) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	if l.fileURL == nil {
+		return
+	}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*ast.Link); ok && entering {
+			if dest, ok := l.rewrite(string(link.Destination)); ok {
+				link.Destination = []byte(dest)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func (l *linkRewriter) rewrite(dest string) (string, bool) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+	p := path.Clean(u.Path)
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", false
+	}
+	if dest := l.fileURL(p); dest != "" {
+		return dest, true
+	}
+	return "", false
+}
@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andrewhowdencom/skr/pkg/index"
+	"github.com/andrewhowdencom/skr/pkg/skill"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+//go:embed templates/*.html
+var templatesEmbed embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"shortDigest": func(d string) string {
+		if _, hex, ok := strings.Cut(d, ":"); ok && len(hex) > 12 {
+			return hex[:12]
+		}
+		return d
+	},
+	"diffLines": diffLines,
+}).ParseFS(templatesEmbed, "templates/*.html"))
+
+// maxTextFileSize is the largest file that is shown in the UI.
+const maxTextFileSize = 1 << 20
+
+// SkillPage is the detail page of a skill, showing its latest release.
+type SkillPage struct {
+	// Root is the URL of the site root, relative to the page.
+	Root string
+	// Registry is the host skills are installed from. If it is empty, the page uses the
+	// host it is served from.
+	Registry     string
+	Skill        index.Skill
+	Body         template.HTML
+	Files        []PageFile
+	Dependencies []Dependency
+}
+
+// PageFile is a file of the skill. Text files link to a FilePage.
+type PageFile struct {
+	store.FileInfo
+	URL string
+}
+
+// Dependency is a dependency of the skill, linked to its page if it is in the index.
+type Dependency struct {
+	Ref string
+	URL string
+}
+
+// FilePage shows a single text file of a skill.
+type FilePage struct {
+	Root    string
+	Skill   index.Skill
+	Path    string
+	Content string
+}
+
+// SkillPath returns the path of the page of the skill in repo, relative to the site root.
+func SkillPath(repo string) string {
+	return "skills/" + repo + "/"
+}
+
+// FilePath returns the path of the page of the file at p in the skill in repo, relative to
+// the site root.
+func FilePath(repo, p string) string {
+	return SkillPath(repo) + "files/" + p + ".html"
+}
+
+// rootFrom returns the URL of the site root from the page at p.
+func rootFrom(p string) string {
+	return strings.Repeat("../", strings.Count(p, "/"))
+}
+
+// LoadSkill reads the latest release of s from st, and returns its page along with the
+// pages of its text files.
+func LoadSkill(ctx context.Context, st *store.Store, idx *index.Index, s index.Skill) (*SkillPage, []*FilePage, error) {
+	ref := s.Repository + ":" + s.Latest
+	desc, err := st.Resolve(ctx, ref)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	manifestBytes, err := content.FetchAll(ctx, st, desc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch manifest for %s: %w", ref, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest for %s: %w", ref, err)
+	}
+
+	page := &SkillPage{
+		Root:     rootFrom(SkillPath(s.Repository)),
+		Registry: idx.Registry,
+		Skill:    s,
+	}
+
+	var files []*FilePage
+	var skillMD []byte
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != store.MediaTypeSkillLayer {
+			continue
+		}
+		rc, err := st.Fetch(ctx, layer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch layer for %s: %w", ref, err)
+		}
+		err = store.WalkLayer(rc, func(entry store.FileInfo, r io.Reader) error {
+			f := PageFile{FileInfo: entry}
+			if entry.Type == "file" && entry.Size <= maxTextFileSize {
+				data, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				if entry.Path == skill.SkillFileName {
+					skillMD = data
+				}
+				if isText(data) {
+					f.URL = "files/" + entry.Path + ".html"
+					files = append(files, &FilePage{
+						Root:    rootFrom(FilePath(s.Repository, entry.Path)),
+						Skill:   s,
+						Path:    entry.Path,
+						Content: string(data),
+					})
+				}
+			}
+			page.Files = append(page.Files, f)
+			return nil
+		})
+		rc.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read layer for %s: %w", ref, err)
+		}
+	}
+
+	if skillMD != nil {
+		fm, err := skill.ParseFrontmatter(skillMD)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s of %s: %w", skill.SkillFileName, ref, err)
+		}
+		page.Body, err = RenderMarkdown(fm.Body, func(p string) string {
+			for _, f := range files {
+				if f.Path == p {
+					return "files/" + p + ".html"
+				}
+			}
+			return ""
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to render %s of %s: %w", skill.SkillFileName, ref, err)
+		}
+	}
+
+	for _, dep := range s.Dependencies {
+		d := Dependency{Ref: dep}
+		if other, ok := findSkill(idx, dep); ok {
+			d.URL = page.Root + SkillPath(other.Repository)
+		}
+		page.Dependencies = append(page.Dependencies, d)
+	}
+
+	return page, files, nil
+}
+
+// findSkill looks up the skill that ref (a tag or digest reference) belongs to.
+func findSkill(idx *index.Index, ref string) (index.Skill, bool) {
+	repo, _, _ := strings.Cut(ref, "@")
+	repo, _ = index.SplitRef(repo)
+	for _, s := range idx.Skills {
+		if s.Repository == repo || (idx.Registry != "" && idx.Registry+"/"+s.Repository == repo) {
+			return s, true
+		}
+	}
+	return index.Skill{}, false
+}
+
+// isText reports whether data looks like text that can be shown in the browser.
+func isText(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
+
+// WriteSkillPage renders the page of a skill.
+func WriteSkillPage(w io.Writer, p *SkillPage) error {
+	return templates.ExecuteTemplate(w, "skill.html", p)
+}
+
+// WriteFilePage renders the page of a file.
+func WriteFilePage(w io.Writer, p *FilePage) error {
+	return templates.ExecuteTemplate(w, "file.html", p)
+}
+
+// FileName returns the base name of the file shown by p.
+func (p *FilePage) FileName() string {
+	return path.Base(p.Path)
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andrewhowdencom/skr/pkg/index"
+	"github.com/andrewhowdencom/skr/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	src := []byte("# Title\n\nSee [the guide](references/guide.md#usage), [missing](missing.md), [outside](../x.md) and [site](https://example.com).\n\n<script>alert(1)</script>\n")
+
+	html, err := RenderMarkdown(src, func(p string) string {
+		if p == "references/guide.md" {
+			return "files/" + p + ".html"
+		}
+		return ""
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(html), `<h1>Title</h1>`)
+	assert.Contains(t, string(html), `href="files/references/guide.md.html"`)
+	assert.Contains(t, string(html), `href="missing.md"`)
+	assert.Contains(t, string(html), `href="../x.md"`)
+	assert.Contains(t, string(html), `href="https://example.com"`)
+	assert.NotContains(t, string(html), "<script>")
+}
+
+func TestLoadSkill(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+
+	build := func(name, content string, files map[string]string) {
+		srcDir := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644))
+		for p, data := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(srcDir, p)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, p), []byte(data), 0644))
+		}
+		require.NoError(t, st.Build(ctx, srcDir, "localhost/skills/"+name+":v1", nil))
+	}
+	build("ocr", "---\nname: ocr\ndescription: OCR\n---\n# OCR\n", nil)
+	build("pdf", "---\nname: pdf\ndescription: PDF\ndependencies:\n  - localhost/skills/ocr:v1\n  - example.com/other:v1\n---\n# PDF\n\nRead [the guide](references/guide.md).\n", map[string]string{
+		"references/guide.md": "# Guide\n",
+		"assets/logo.bin":     "\x00\x01",
+	})
+
+	idx, err := index.FromStore(ctx, st, index.Options{})
+	require.NoError(t, err)
+	require.Len(t, idx.Skills, 2)
+
+	page, files, err := LoadSkill(ctx, st, idx, idx.Skills[1])
+	require.NoError(t, err)
+
+	assert.Equal(t, "../../../../", page.Root)
+	assert.Contains(t, string(page.Body), `href="files/references/guide.md.html"`)
+	assert.Equal(t, []Dependency{
+		{Ref: "localhost/skills/ocr:v1", URL: "../../../../skills/localhost/skills/ocr/"},
+		{Ref: "example.com/other:v1"},
+	}, page.Dependencies)
+
+	urls := make(map[string]string)
+	for _, f := range page.Files {
+		urls[f.Path] = f.URL
+	}
+	assert.Equal(t, "files/SKILL.md.html", urls["SKILL.md"])
+	assert.Equal(t, "files/references/guide.md.html", urls["references/guide.md"])
+	assert.Equal(t, "", urls["assets/logo.bin"])
+
+	require.Len(t, files, 2)
+	assert.Equal(t, "references/guide.md", files[1].Path)
+	assert.Equal(t, "../../../../../../", files[1].Root)
+	assert.Equal(t, "# Guide\n", files[1].Content)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSkillPage(&buf, page))
+	assert.Contains(t, buf.String(), `<a href="../../../../skills/localhost/skills/ocr/">localhost/skills/ocr:v1</a>`)
+	buf.Reset()
+	require.NoError(t, WriteFilePage(&buf, files[1]))
+	assert.Contains(t, buf.String(), `<pre class="file-content"># Guide`)
+}
+
+func TestLoadDiffs(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := store.New(t.TempDir())
+	require.NoError(t, err)
+	for tag, body := range map[string]string{"v1": "Old <b>text</b>.\n", "v2": "New text.\n"} {
+		srcDir := filepath.Join(t.TempDir(), "pdf")
+		require.NoError(t, os.MkdirAll(srcDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("---\nname: pdf\ndescription: PDF\n---\n"+body), 0644))
+		require.NoError(t, st.Build(ctx, srcDir, "localhost/skills/pdf:"+tag, nil))
+	}
+
+	idx, err := index.FromStore(ctx, st, index.Options{})
+	require.NoError(t, err)
+	require.Len(t, idx.Skills, 1)
+
+	pages, err := LoadDiffs(ctx, st, idx.Skills[0])
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+	assert.Equal(t, "v1", pages[0].From)
+	assert.Equal(t, "v2", pages[0].To)
+	assert.Equal(t, "skills/localhost/skills/pdf/diff/v1/v2.html", DiffPath("localhost/skills/pdf", "v1", "v2"))
+	assert.Equal(t, "../../../../../../", pages[0].Root)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiffPage(&buf, pages[0]))
+	assert.Contains(t, buf.String(), `<span class="diff-del">-Old &lt;b&gt;text&lt;/b&gt;.</span><span class="diff-add">&#43;New text.</span>`)
+}
+
+func TestDiffLines(t *testing.T) {
+	unified := "--- a/SKILL.md\n+++ b/SKILL.md\n@@ -1,3 +1,3 @@\n----\n+++ title\n context\n"
+
+	var classes []string
+	for _, l := range diffLines(unified) {
+		classes = append(classes, l.Class)
+	}
+	assert.Equal(t, []string{"diff-file", "diff-file", "diff-hunk", "diff-del", "diff-add", "diff-context"}, classes)
+}